@@ -0,0 +1,105 @@
+package menubotlib_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+const shopScheduleYAML = `
+timezone: Africa/Johannesburg
+weekly:
+  monday: ["08:00-17:00"]
+  friday: ["08:00-12:00", "14:00-17:00"]
+holidays: ["2026-10-19"]
+`
+
+func Test_ShopSchedule(t *testing.T) {
+	schedule, err := mb.ParseShopSchedule([]byte(shopScheduleYAML), "yaml")
+	assert.NoError(t, err)
+
+	sast, err := time.LoadLocation("Africa/Johannesburg")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		at       time.Time
+		isOpen   bool
+		nextOpen time.Time
+	}{
+		// Monday the 19th is a holiday
+		{time.Date(2026, 10, 19, 10, 0, 0, 0, sast), false, time.Date(2026, 10, 23, 8, 0, 0, 0, sast)},
+		{time.Date(2026, 10, 23, 12, 30, 0, 0, sast), false, time.Date(2026, 10, 23, 14, 0, 0, 0, sast)},
+		{time.Date(2026, 10, 23, 14, 0, 0, 0, sast), true, time.Date(2026, 10, 26, 8, 0, 0, 0, sast)},
+		// 06:30 UTC is 08:30 in Johannesburg
+		{time.Date(2026, 10, 26, 6, 30, 0, 0, time.UTC), true, time.Date(2026, 10, 30, 8, 0, 0, 0, sast)},
+		{time.Date(2026, 10, 26, 17, 0, 0, 0, sast), false, time.Date(2026, 10, 30, 8, 0, 0, 0, sast)},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.isOpen, schedule.IsOpen(test.at), test.at)
+		next, err := schedule.NextOpening(test.at)
+		assert.NoError(t, err)
+		assert.True(t, test.nextOpen.Equal(next), "%v: got %v want %v", test.at, next, test.nextOpen)
+	}
+
+	_, err = mb.ParseShopSchedule([]byte(`weekly: {funday: ["08:00-17:00"]}`), "yaml")
+	assert.EqualError(t, err, "unknown day of the week: funday")
+	_, err = mb.ParseShopSchedule([]byte(`{"weekly": {"monday": ["17:00-08:00"]}}`), "json")
+	assert.EqualError(t, err, `opening hours "17:00-08:00" for monday close before they open`)
+}
+
+func Test_ClosedShopAllowsBrowsing(t *testing.T) {
+	db := setupTestDB(t)
+
+	schedule, err := mb.ParseShopSchedule([]byte(shopScheduleYAML), "yaml")
+	assert.NoError(t, err)
+
+	sast, err := time.LoadLocation("Africa/Johannesburg")
+	assert.NoError(t, err)
+	closedConvo := func(message string) *mb.ConversationContext {
+		return &mb.ConversationContext{
+			UserExisted: true,
+			Pricelist:   mb.Pricelist{Catalogue: selections},
+			MessageBody: message,
+			DBReadTime:  time.Date(2026, 10, 23, 20, 0, 0, 0, sast),
+			Schedule:    schedule,
+		}
+	}
+
+	response := mb.GetResponseToMsg(closedConvo("update order 9:12"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Sorry, we're closed at the moment, orders open Monday 26 October at 08:00. You can still browse the price list with fr.prlist?", response)
+
+	response = mb.GetResponseToMsg(closedConvo("checkoutnow?"), db, mb.CheckoutInfo{}, true)
+	assert.True(t, strings.HasPrefix(response, "Sorry, we're closed at the moment"), response)
+
+	response = mb.GetResponseToMsg(closedConvo("item 9?"), db, mb.CheckoutInfo{}, true)
+	assert.True(t, strings.HasPrefix(response, "9: Unchargeable cellphone @ R150 each"), response)
+}
+
+func Test_HiddenItems(t *testing.T) {
+	db := setupAvailability(t)
+
+	availability, err := mb.GetItemAvailabilityFromDB(db, catalogueID)
+	assert.NoError(t, err)
+	marked := availability.Apply(selections)
+
+	priceList := mb.AssembleCatalogueSelections("", marked)
+	assert.NotContains(t, priceList, "Macless Apple")
+	assert.Contains(t, priceList, "Unchargeable cellphone")
+	assert.Contains(t, priceList, "   1. Vacuumless roomba version @ R650\n   3. Floppy handled kinetic version @ R650\n")
+
+	response := mb.GetResponseToMsg(availabilityConvo("item 8?"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Sorry, item 8 isn't available at the moment.", response)
+
+	response = mb.GetResponseToMsg(availabilityConvo("update order 8:1, 7:1x1, 2x1"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Sorry, 7: Bristleless Broom, Bristled handleless version @ R650 and 8: Macless Apple @ R100 each isn't available at the moment. Please change your order.", response)
+
+	response = mb.GetResponseToMsg(availabilityConvo("update order 7:1x1, 9:1"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "successfully updated current order", response)
+
+	// Free text orders don't match hidden items
+	response = mb.GetResponseToMsg(availabilityConvo("order 2 apples"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, `Sorry, I couldn't find "apples" on the price list.`, response)
+}
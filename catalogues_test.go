@@ -0,0 +1,97 @@
+package menubotlib_test
+
+import (
+	"database/sql"
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	retailNumber    = "0766140000"
+	wholesaleNumber = "0820000000"
+)
+
+func setupCatalogueSet(t *testing.T) (*sql.DB, *mb.CatalogueSet) {
+	db := setupTestDB(t, crtUserInfoTbl, crtCustomerOrderTbl)
+	retail, err := mb.NewPricelist("retail", "Retail prices.", selections)
+	assert.NoError(t, err)
+	wholesale, err := mb.NewPricelist("wholesale", "Wholesale prices.", []mb.CatalogueSelection{EdiblesSelection})
+	assert.NoError(t, err)
+
+	set, err := mb.NewCatalogueSet(retail, wholesale)
+	assert.NoError(t, err)
+	set.Rules = []mb.CatalogueRule{mb.CellNumberRule("wholesale", wholesaleNumber)}
+	return db, set
+}
+
+func respond(db *sql.DB, set *mb.CatalogueSet, cellNumber, message string) (*mb.ConversationContext, string) {
+	convo := mb.NewConversationContextWithCatalogues(db, cellNumber, message, set, true)
+	return convo, mb.GetResponseToMsg(convo, db, mb.CheckoutInfo{}, true)
+}
+
+func Test_CatalogueSetRules(t *testing.T) {
+	db, set := setupCatalogueSet(t)
+
+	convo, _ := respond(db, set, retailNumber, "hi")
+	assert.Equal(t, "retail", convo.Pricelist.CatalogueID)
+
+	convo, response := respond(db, set, wholesaleNumber, "catalogues?")
+	assert.Equal(t, "wholesale", convo.Pricelist.CatalogueID)
+	assert.Contains(t, response, "Catalogues:\n* retail\n* wholesale (current)")
+
+	_, err := mb.NewCatalogueSet(set.Pricelists["retail"], set.Pricelists["retail"])
+	assert.EqualError(t, err, "catalogue retail is in the set twice")
+}
+
+func Test_UseCatalogue(t *testing.T) {
+	db, set := setupCatalogueSet(t)
+
+	respond(db, set, retailNumber, "hi")
+
+	_, response := respond(db, set, retailNumber, "use catalogue bulk")
+	assert.Equal(t, "There is no catalogue called bulk, please choose one of: retail, wholesale", response)
+
+	_, response = respond(db, set, retailNumber, "use catalogue Wholesale")
+	assert.Equal(t, "You are now using the wholesale catalogue, send fr.prlist? to see it.", response)
+
+	// The choice is remembered and new orders are tied to the catalogue
+	convo, response := respond(db, set, retailNumber, "update order 10:1x2")
+	assert.Equal(t, "wholesale", convo.Pricelist.CatalogueID)
+	assert.Equal(t, "successfully updated current order", response)
+
+	var order mb.CustomerOrder
+	err := order.SetCurrentOrderFromDB(db, retailNumber, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "wholesale", order.CatalogueID)
+
+	_, response = respond(db, set, retailNumber, "use catalogue retail")
+	assert.Equal(t, "Your current order was built from the wholesale catalogue, please check it out or empty it before switching.", response)
+
+	// An open order stays with its catalogue whatever the user is now assigned
+	_, err = db.Exec(`UPDATE userinfo SET catalogueID = 'retail' WHERE cellnumber = ?`, retailNumber)
+	assert.NoError(t, err)
+	convo, _ = respond(db, set, retailNumber, "currentorder?")
+	assert.Equal(t, "wholesale", convo.Pricelist.CatalogueID)
+}
+
+func Test_CatalogueReplyMessages(t *testing.T) {
+	db, set := setupCatalogueSet(t)
+
+	_, err := mb.NewPricelist("", "Retail prices.", selections)
+	assert.EqualError(t, err, "a pricelist needs a catalogue ID")
+
+	messages, err := mb.ParseMessages([]byte(`sameCatalogue: "{{.Name}} is already your catalogue."`), "yaml")
+	assert.NoError(t, err)
+	respond(db, set, retailNumber, "hi")
+
+	convo := mb.NewConversationContextWithCatalogues(db, retailNumber, "use catalogue retail", set, true)
+	convo.Messages = messages
+	assert.Equal(t, "retail is already your catalogue.", mb.GetResponseToMsg(convo, db, mb.CheckoutInfo{}, true))
+
+	// Messages the shop leaves out keep their default text
+	convo = mb.NewConversationContextWithCatalogues(db, retailNumber, "use catalogue bulk", set, true)
+	convo.Messages = messages
+	assert.Equal(t, "There is no catalogue called bulk, please choose one of: retail, wholesale", mb.GetResponseToMsg(convo, db, mb.CheckoutInfo{}, true))
+}
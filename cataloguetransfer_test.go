@@ -0,0 +1,114 @@
+package menubotlib_test
+
+import (
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CatalogueExportParseRoundTrip(t *testing.T) {
+	for _, format := range []string{"csv", "json", "yaml"} {
+		data, err := mb.ExportCatalogue(selections, format)
+		assert.NoError(t, err, format)
+
+		parsed, err := mb.ParseCatalogue(data, format, catalogueID)
+		assert.NoError(t, err, format)
+		assert.Equal(t, selections, parsed, format)
+	}
+}
+
+func Test_ParseCatalogueCSV(t *testing.T) {
+	spreadsheet := "selection,itemID,item,pricingType,options\n" +
+		"Gardening:,1,Denitrified fertilizer,WeightItem,5g @ R110 p.g.|10g @ R90 p.g.\n" +
+		"Tech:,8,Macless Apple @ R100 each,SingleItem,\n" +
+		"Gardening:,3,Decarbonized soil,WeightItem,5g @ R150 p.g. | 10g @ R130 p.g.\n"
+
+	parsed, err := mb.ParseCatalogue([]byte(spreadsheet), "csv", catalogueID)
+	assert.NoError(t, err)
+	assert.Equal(t, []mb.CatalogueSelection{
+		{Preamble: grdngSlctnPreamble, Items: []mb.CatalogueItem{
+			GardeningSelection.Items[0],
+			GardeningSelection.Items[2],
+		}},
+		{Preamble: tchSlctnPreamble, Items: []mb.CatalogueItem{
+			TechSelection.Items[0],
+		}},
+	}, parsed)
+
+	_, err = mb.ParseCatalogue([]byte("selection,itemID,item,pricingType,options\nTech:,eight,Apple,SingleItem,\n"), "csv", catalogueID)
+	assert.EqualError(t, err, `catalogue line 2: item ID "eight" is not a number`)
+
+	_, err = mb.ParseCatalogue([]byte("section,itemID,item,pricingType,options\n"), "csv", catalogueID)
+	assert.EqualError(t, err, "catalogue column 1 should be selection but is section")
+}
+
+func Test_ImportCatalogue(t *testing.T) {
+	db := setupTestDB(t, crtCatalogueItemTbl, crtCatalogueVersionTbl)
+
+	err := mb.InsertCatalogueItems(db, []mb.CatalogueSelection{GardeningSelection, TechSelection})
+	assert.NoError(t, err)
+
+	yamlCatalogue := `
+selections:
+  - preamble: "Gardening:"
+    items:
+      - id: 1
+        item: Denitrified fertilizer
+        pricingType: WeightItem
+        options: ["5g @ R120 p.g.", "10g @ R100 p.g."]
+      - id: 2
+        item: Dehydrogenated water
+        pricingType: WeightItem
+        options: ["5g @ R140 p.g.", "10g @ R120 p.g."]
+      - id: 13
+        item: Leafless lettuce seeds
+        pricingType: WeightItem
+        options: ["5g @ R20 p.g.", "10g @ R15 p.g."]
+`
+	incoming, err := mb.ParseCatalogue([]byte(yamlCatalogue), "yaml", catalogueID)
+	assert.NoError(t, err)
+
+	// A dry run only previews the import
+	diff, err := mb.ImportCatalogue(db, catalogueID, 1, incoming, true)
+	assert.NoError(t, err)
+	assert.Equal(t, `+ 13: Leafless lettuce seeds [Gardening:, WeightItem, 5g @ R20 p.g.|10g @ R15 p.g.]
+~ 1: Denitrified fertilizer [Gardening:, WeightItem, 5g @ R110 p.g.|10g @ R90 p.g.]
+  => Denitrified fertilizer [Gardening:, WeightItem, 5g @ R120 p.g.|10g @ R100 p.g.]
+? 3: Decarbonized soil is not in the import and will be kept
+? 8: Macless Apple @ R100 each is not in the import and will be kept
+? 9: Unchargeable cellphone @ R150 each is not in the import and will be kept`, diff.String())
+
+	exported, err := mb.ExportCatalogueFromDB(db, catalogueID, "json")
+	assert.NoError(t, err)
+	unchanged, err := mb.ExportCatalogue([]mb.CatalogueSelection{GardeningSelection, TechSelection}, "json")
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(unchanged), string(exported))
+
+	_, err = mb.ImportCatalogue(db, catalogueID, 1, incoming, false)
+	assert.NoError(t, err)
+
+	ctlgItms, err := mb.GetCatalogueItemsFromDB(db, catalogueID)
+	assert.NoError(t, err)
+	// Item 13 is read with the rest of its selection rather than after the tech items
+	var itemIDs []int
+	for _, item := range ctlgItms {
+		itemIDs = append(itemIDs, item.CatalogueItemID)
+	}
+	assert.Equal(t, []int{1, 2, 3, 13, 8, 9}, itemIDs)
+	imported := mb.CmpsCtlgSlctnsFromCtlgItms(ctlgItms)
+	assert.Len(t, imported, 2)
+	assert.Equal(t, []string{"5g @ R120 p.g.", "10g @ R100 p.g."}, imported[0].Items[0].Options)
+	assert.Equal(t, "Leafless lettuce seeds", imported[0].Items[3].Item)
+	assert.Equal(t, TechSelection, imported[1])
+
+	// Importing the same catalogue again changes nothing
+	diff, err = mb.ImportCatalogue(db, catalogueID, 1, incoming, false)
+	assert.NoError(t, err)
+	assert.True(t, diff.IsEmpty())
+
+	// An invalid catalogue is rejected before anything is saved
+	incoming[0].Items[0].Options = []string{"lots @ R1"}
+	_, err = mb.ImportCatalogue(db, catalogueID, 1, incoming, false)
+	assert.ErrorContains(t, err, "item 1 (Denitrified fertilizer) option 1")
+}
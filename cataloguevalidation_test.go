@@ -0,0 +1,64 @@
+package menubotlib_test
+
+import (
+	"errors"
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ValidateCatalogue(t *testing.T) {
+	assert.NoError(t, mb.ValidateCatalogue(selections))
+
+	invalid := []mb.CatalogueSelection{
+		{
+			Preamble: "Broken:",
+			Items: []mb.CatalogueItem{
+				{CatalogueItemID: 1, Item: "Duplicate", Options: []string{"Single @ R10"}, PricingType: mb.SingleItem},
+				{CatalogueItemID: 2, Item: "Unsorted weights", Options: []string{"10g @ R90 p.g.", "5g @ R110 p.g."}, PricingType: mb.WeightItem},
+				{CatalogueItemID: 3, Item: "Unpriced option", Options: []string{"Large", "Small @ R5"}, PricingType: mb.SingleItem},
+				{CatalogueItemID: 4, Item: "Unknown pricing", Options: []string{"Single @ R10"}, PricingType: "BulkItem"},
+			},
+		},
+		{Preamble: "", Items: nil},
+		GardeningSelection,
+	}
+
+	err := mb.ValidateCatalogue(invalid)
+	var report *mb.CatalogueValidationError
+	assert.True(t, errors.As(err, &report))
+	assert.Equal(t, []string{
+		`item 2 (Unsorted weights) option 2 "5g @ R110 p.g." must be a larger weight than the option before it`,
+		`item 3 (Unpriced option) option 1 "Large" has no price in the format @ R200`,
+		`item 4 (Unknown pricing) has unknown pricing type "BulkItem"`,
+		"selection 2 has no preamble",
+		`selection "" has no items`,
+		"item 1 (Denitrified fertilizer) has the same item number as Duplicate",
+		"item 2 (Dehydrogenated water) has the same item number as Unsorted weights",
+		"item 3 (Decarbonized soil) has the same item number as Unpriced option",
+	}, report.Problems)
+}
+
+func Test_LoadPricelistFromDB(t *testing.T) {
+	db := setupTestDB(t, crtCatalogueItemTbl, crtCatalogueVersionTbl, crtCatalogueSelectionTbl)
+
+	err := mb.InsertCatalogueItems(db, selections)
+	assert.NoError(t, err)
+
+	prlst, err := mb.LoadPricelistFromDB(db, catalogueID, "All fertilizer quoted per gram.")
+	assert.NoError(t, err)
+	assert.Equal(t, selections, prlst.Catalogue)
+
+	// A catalogue which can't be priced stops the bot from starting
+	_, err = db.Exec(`UPDATE catalogueitem SET "options" = '["5g"]' WHERE catalogueitemID = 1`)
+	assert.NoError(t, err)
+	_, err = mb.LoadPricelistFromDB(db, catalogueID, "All fertilizer quoted per gram.")
+	assert.ErrorContains(t, err, `item 1 (Denitrified fertilizer) option 1 "5g" is not in the format 5g @ R110`)
+
+	// Options which aren't JSON are reported rather than read as no options
+	_, err = db.Exec(`UPDATE catalogueitem SET "options" = 'not json' WHERE catalogueitemID = 1`)
+	assert.NoError(t, err)
+	_, err = mb.LoadPricelistFromDB(db, catalogueID, "All fertilizer quoted per gram.")
+	assert.ErrorContains(t, err, "failed to read the options of catalogue item 1")
+}
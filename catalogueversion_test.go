@@ -0,0 +1,217 @@
+package menubotlib_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupVersionedCatalogue(t *testing.T) *sql.DB {
+	db := setupTestDB(t, crtCatalogueItemTbl, crtCatalogueVersionTbl, crtCatalogueSelectionTbl, crtCustomerOrderTbl)
+	assert.NoError(t, mb.InsertCatalogueItems(db, selections))
+	return db
+}
+
+// repriceFertilizer changes the price of item 1 in a draft version
+func repriceFertilizer(t *testing.T, db *sql.DB, version int, options ...string) {
+	items, err := mb.GetCatalogueVersionItemsFromDB(db, catalogueID, version)
+	assert.NoError(t, err)
+	draft := mb.CmpsCtlgSlctnsFromCtlgItms(items)
+	draft[0].Items[0].Options = options
+
+	_, err = mb.ImportCatalogue(db, catalogueID, version, draft, false)
+	assert.NoError(t, err)
+}
+
+func Test_CatalogueVersionPublishAndHistory(t *testing.T) {
+	db := setupVersionedCatalogue(t)
+
+	// Catalogues stored before versioning are version 1
+	version, err := mb.GetLiveCatalogueVersion(db, catalogueID, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, version)
+
+	draft, err := mb.CreateDraftCatalogueVersion(db, catalogueID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, draft)
+	repriceFertilizer(t, db, draft, "5g @ R120 p.g.", "10g @ R100 p.g.")
+
+	// The draft isn't live until it is published
+	live, err := mb.GetCatalogueItemsFromDB(db, catalogueID)
+	assert.NoError(t, err)
+	assert.Equal(t, GardeningSelection.Items[0], live[0])
+
+	// The version stored before versioning is published once there is a draft, it can't be changed in place
+	_, err = mb.ImportCatalogue(db, catalogueID, 1, selections, false)
+	assert.EqualError(t, err, "version 1 of catalogue Pig is published, please create a draft to change it")
+
+	// Publishing in the past would rewrite what was charged
+	lastWeek := time.Now().Add(-7 * 24 * time.Hour)
+	err = mb.PublishCatalogueVersion(db, catalogueID, draft, lastWeek)
+	assert.EqualError(t, err, "version 2 of catalogue Pig can't take effect in the past, please publish it now or later")
+
+	beforePublishing := time.Now().Add(-time.Hour)
+	err = mb.PublishCatalogueVersion(db, catalogueID, draft, time.Time{})
+	assert.NoError(t, err)
+
+	live, err = mb.GetCatalogueItemsFromDB(db, catalogueID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"5g @ R120 p.g.", "10g @ R100 p.g."}, live[0].Options)
+
+	// What the price was before the change
+	before, version, err := mb.GetCatalogueItemsAt(db, catalogueID, beforePublishing)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, version)
+	assert.Equal(t, GardeningSelection.Items[0], before[0])
+
+	// Published versions can't be changed or published again
+	_, err = mb.ImportCatalogue(db, catalogueID, draft, selections, false)
+	assert.EqualError(t, err, "version 2 of catalogue Pig is published, please create a draft to change it")
+	_, err = mb.ImportCatalogue(db, catalogueID, 1, selections, false)
+	assert.EqualError(t, err, "version 1 of catalogue Pig is published, please create a draft to change it")
+	err = mb.PublishCatalogueVersion(db, catalogueID, draft, time.Time{})
+	assert.EqualError(t, err, "version 2 of catalogue Pig is already published")
+
+	versions, err := mb.GetCatalogueVersions(db, catalogueID)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 2)
+	assert.False(t, versions[0].IsDraft())
+	assert.False(t, versions[1].IsDraft())
+}
+
+func Test_CatalogueVersionScheduled(t *testing.T) {
+	db := setupVersionedCatalogue(t)
+
+	prlst, err := mb.LoadPricelistFromDB(db, catalogueID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, prlst.Version)
+
+	draft, err := mb.CreateDraftCatalogueVersion(db, catalogueID)
+	assert.NoError(t, err)
+	repriceFertilizer(t, db, draft, "5g @ R130 p.g.", "10g @ R110 p.g.")
+
+	nextWeek := time.Now().Add(7 * 24 * time.Hour)
+	err = mb.PublishCatalogueVersion(db, catalogueID, draft, nextWeek)
+	assert.NoError(t, err)
+
+	// A scheduled version leaves the current prices alone until it takes effect
+	refreshed, err := prlst.RefreshFromDB(db)
+	assert.NoError(t, err)
+	assert.False(t, refreshed)
+	assert.Equal(t, GardeningSelection.Items[0], prlst.Catalogue[0].Items[0])
+
+	version, err := mb.GetLiveCatalogueVersion(db, catalogueID, nextWeek.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, draft, version)
+
+	// An invalid draft can't be published
+	invalidDraft, err := mb.CreateDraftCatalogueVersion(db, catalogueID)
+	assert.NoError(t, err)
+	_, err = db.Exec(`UPDATE catalogueitem SET "options" = '["5g"]' WHERE catalogueID = ? AND version = ? AND catalogueitemID = 1`, catalogueID, invalidDraft)
+	assert.NoError(t, err)
+	err = mb.PublishCatalogueVersion(db, catalogueID, invalidDraft, time.Time{})
+	assert.ErrorContains(t, err, `item 1 (Denitrified fertilizer) option 1 "5g"`)
+}
+
+func Test_OrderRecordsCatalogueVersion(t *testing.T) {
+	db := setupVersionedCatalogue(t)
+
+	draft, err := mb.CreateDraftCatalogueVersion(db, catalogueID)
+	assert.NoError(t, err)
+	err = mb.PublishCatalogueVersion(db, catalogueID, draft, time.Time{})
+	assert.NoError(t, err)
+
+	prlst, err := mb.LoadPricelistFromDB(db, catalogueID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, draft, prlst.Version)
+
+	custOrd := mb.CustomerOrder{OrderID: 1, CellNumber: "0766140000"}
+	custOrd.PriceAgainst(prlst)
+	err = custOrd.UpdateOrInsertCurrentOrder(db, custOrd.CellNumber, mb.OrderItems{
+		MenuIndications: []mb.MenuIndication{{ItemMenuNum: 1, ItemAmount: mb.WeightQuantity(5)}},
+	}, true)
+	assert.NoError(t, err)
+
+	var stored mb.CustomerOrder
+	err = stored.SetCurrentOrderFromDB(db, custOrd.CellNumber, true)
+	assert.NoError(t, err)
+	assert.Equal(t, catalogueID, stored.CatalogueID)
+	assert.Equal(t, draft, stored.CatalogueVersion)
+}
+
+func Test_OpenOrderKeepsCatalogueVersion(t *testing.T) {
+	db := setupVersionedCatalogue(t)
+
+	v1, err := mb.LoadPricelistFromDB(db, catalogueID, "")
+	assert.NoError(t, err)
+	// A fixed discount makes the summary show the subtotal
+	promotions := []mb.Promotion{{Description: "R10 off", Type: mb.FixedDiscount, Amount: 10}}
+	convo := func(prlst mb.Pricelist, message string) *mb.ConversationContext {
+		return &mb.ConversationContext{
+			UserInfo:     mb.UserInfo{CellNumber: "0766140000"},
+			UserExisted:  true,
+			Pricelist:    prlst,
+			CurrentOrder: mb.CustomerOrder{OrderID: 1, CellNumber: "0766140000"},
+			MessageBody:  message,
+			Promotions:   promotions,
+		}
+	}
+	response := mb.GetResponseToMsg(convo(v1, "update order 1:5"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "successfully updated current order", response)
+
+	// Version 2 puts the price up while the cart is open
+	draft, err := mb.CreateDraftCatalogueVersion(db, catalogueID)
+	assert.NoError(t, err)
+	repriceFertilizer(t, db, draft, "5g @ R120 p.g.", "10g @ R100 p.g.")
+	assert.NoError(t, mb.PublishCatalogueVersion(db, catalogueID, draft, time.Time{}))
+	v2, err := mb.LoadPricelistFromDB(db, catalogueID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, draft, v2.Version)
+
+	// Changing the open order doesn't move it to the new version
+	response = mb.GetResponseToMsg(convo(v2, "update order 1:10"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "successfully updated current order", response)
+	var stored mb.CustomerOrder
+	assert.NoError(t, stored.SetCurrentOrderFromDB(db, "0766140000", true))
+	assert.Equal(t, 1, stored.CatalogueVersion)
+
+	// 10g at the version 1 price of R90 p.g. rather than R100
+	response = mb.GetResponseToMsg(convo(v2, "checkoutnow?"), db, mb.CheckoutInfo{}, true)
+	assert.True(t, strings.HasPrefix(response, "Subtotal: R900\n"), response)
+}
+
+func Test_CatalogueSetRefreshesPublishedVersion(t *testing.T) {
+	db := setupTestDB(t, crtUserInfoTbl, crtCatalogueItemTbl, crtCatalogueVersionTbl, crtCatalogueSelectionTbl, crtCustomerOrderTbl)
+	assert.NoError(t, mb.InsertCatalogueItems(db, selections))
+	set, err := mb.LoadCatalogueSetFromDB(db, "", catalogueID)
+	assert.NoError(t, err)
+
+	publish := func() int {
+		draft, err := mb.CreateDraftCatalogueVersion(db, catalogueID)
+		assert.NoError(t, err)
+		repriceFertilizer(t, db, draft, "5g @ R120 p.g.", "10g @ R100 p.g.")
+		assert.NoError(t, mb.PublishCatalogueVersion(db, catalogueID, draft, time.Time{}))
+		return draft
+	}
+
+	// A conversation built after publishing sees the new version
+	v2 := publish()
+	convo := mb.NewConversationContextWithCatalogues(db, "0766140000", "menu?", set, true)
+	assert.Equal(t, v2, convo.Pricelist.Version)
+	assert.Equal(t, v2, set.PricelistFor(mb.UserInfo{}).Version)
+
+	// Hosts can refresh the whole set
+	v3 := publish()
+	refreshed, err := set.RefreshFromDB(db)
+	assert.NoError(t, err)
+	assert.True(t, refreshed)
+	assert.Equal(t, v3, set.PricelistFor(mb.UserInfo{}).Version)
+
+	refreshed, err = set.RefreshFromDB(db)
+	assert.NoError(t, err)
+	assert.False(t, refreshed)
+}
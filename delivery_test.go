@@ -0,0 +1,129 @@
+package menubotlib_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+const deliveryZonesYAML = `
+origin: {lat: -33.9249, lng: 18.4241}
+zones:
+  - name: City Bowl
+    suburbs: [Gardens, Tamboerskloof]
+    postcodes: ["8001"]
+    fee: 30
+  - name: Greater Cape Town
+    radiusKm: 25
+    fee: 60
+    minOrder: 300
+`
+
+func Test_DeliveryZones(t *testing.T) {
+	zones, err := mb.ParseDeliveryZones([]byte(deliveryZonesYAML), "yaml")
+	assert.NoError(t, err)
+	zones.Geocode = func(address string) (mb.GeoPoint, error) {
+		if address == "Stellenbosch" {
+			return mb.GeoPoint{Lat: -33.9321, Lng: 18.8602}, nil
+		}
+		return mb.GeoPoint{}, errors.New("address not found")
+	}
+
+	tests := []struct {
+		address string
+		zone    string
+		found   bool
+	}{
+		{"12 Long Street, gardens", "City Bowl", true},
+		{"3 Bree Street, Cape Town, 8001", "City Bowl", true},
+		{"Sea Point -33.9155, 18.3869", "Greater Cape Town", true},
+		{"Stellenbosch", "", false},
+		{"Gardenside -34.5, 19.5", "", false},
+	}
+	for _, test := range tests {
+		zone, found, err := zones.ZoneFor(test.address)
+		assert.NoError(t, err, test.address)
+		assert.Equal(t, test.found, found, test.address)
+		assert.Equal(t, test.zone, zone.Name, test.address)
+	}
+
+	_, _, err = zones.ZoneFor("Nowhere")
+	assert.ErrorContains(t, err, "while finding Nowhere: address not found")
+
+	_, err = mb.ParseDeliveryZones([]byte(`{"zones": [{"name": "Far", "radiusKm": 40, "fee": 90}]}`), "json")
+	assert.EqualError(t, err, "delivery zone 1 (Far): a zone with a radius needs the origin it's measured from")
+}
+
+func setupDelivery(t *testing.T) *sql.DB {
+	db := setupTestDB(t, crtCustomerOrderTbl, crtUserInfoTbl)
+	_, err := db.Exec(`INSERT INTO userinfo (cellnumber) VALUES ('0766140000')`)
+	assert.NoError(t, err)
+	return db
+}
+
+func deliveryConvo(t *testing.T, db *sql.DB, message string) *mb.ConversationContext {
+	zones, err := mb.ParseDeliveryZones([]byte(deliveryZonesYAML), "yaml")
+	assert.NoError(t, err)
+
+	userInfo := mb.UserInfo{CellNumber: "0766140000"}
+	assert.NoError(t, userInfo.SetUserInfoFromDB(db))
+	return &mb.ConversationContext{
+		UserInfo:    userInfo,
+		UserExisted: true,
+		Pricelist:   mb.Pricelist{Catalogue: selections, CatalogueID: catalogueID},
+		MessageBody: message,
+		Delivery:    zones,
+	}
+}
+
+func Test_DeliveryAddressCommands(t *testing.T) {
+	db := setupDelivery(t)
+
+	response := mb.GetResponseToMsg(deliveryConvo(t, db, "Update address: 12 Long Street, Gardens"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "successfully updated user info.address to 12 Long Street, Gardens Delivery to City Bowl costs R30.", response)
+
+	convo := deliveryConvo(t, db, "userinfo?")
+	assert.Equal(t, "12 Long Street, Gardens", convo.UserInfo.Address.String)
+
+	// An order can go somewhere else
+	response = mb.GetResponseToMsg(deliveryConvo(t, db, "deliver to: Sea Point -33.9155, 18.3869"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "This order will be delivered to Sea Point -33.9155, 18.3869. Delivery to Greater Cape Town costs R60 on orders of R300 or more.", response)
+
+	response = mb.GetResponseToMsg(deliveryConvo(t, db, "deliver to: Stellenbosch"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "This order will be delivered to Stellenbosch. Sorry, we don't deliver there.", response)
+
+	var order mb.CustomerOrder
+	assert.NoError(t, order.SetCurrentOrderFromDB(db, "0766140000", true))
+	assert.Equal(t, "Stellenbosch", order.OrderItems.Address)
+}
+
+func Test_DeliveryCheckout(t *testing.T) {
+	db := setupDelivery(t)
+
+	checkout := func() string {
+		return mb.GetResponseToMsg(deliveryConvo(t, db, "checkoutnow?"), db, mb.CheckoutInfo{}, true)
+	}
+
+	response := mb.GetResponseToMsg(deliveryConvo(t, db, "update order 9:1"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "successfully updated current order", response)
+	assert.Equal(t, "Please tell us where to deliver, type & send-: update address: 12 Long Street, Gardens, 8001", checkout())
+
+	mb.GetResponseToMsg(deliveryConvo(t, db, "deliver to: Stellenbosch"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Sorry, we don't deliver to Stellenbosch.", checkout())
+
+	mb.GetResponseToMsg(deliveryConvo(t, db, "deliver to: Sea Point -33.9155, 18.3869"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Sorry, delivery to Greater Cape Town is for orders of R300 or more, your order comes to R150.", checkout())
+
+	mb.GetResponseToMsg(deliveryConvo(t, db, "update order 9:2"), db, mb.CheckoutInfo{}, true)
+	response = checkout()
+	assert.Contains(t, response, "Subtotal: R300\nDelivery (Greater Cape Town): R60\nTotal: R360\n")
+
+	var order mb.CustomerOrder
+	assert.NoError(t, order.SetCurrentOrderFromDB(db, "0766140000", true))
+	assert.Equal(t, "Greater Cape Town", order.OrderItems.DeliveryZone)
+	assert.Equal(t, 60, order.OrderItems.DeliveryFee)
+	assert.Contains(t, order.GetCurrentOrderAsAString(db, "0766140000", true), "\nDeliver to: Sea Point -33.9155, 18.3869")
+}
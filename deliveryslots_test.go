@@ -0,0 +1,131 @@
+package menubotlib_test
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+const deliverySlotsYAML = `
+timezone: Africa/Johannesburg
+daysAhead: 2
+capacity: 2
+weekly:
+  monday: ["09:00-12:00", "14:00-17:00 x 1"]
+  tuesday: ["09:00-12:00"]
+`
+
+func setupSlots(t *testing.T) (*sql.DB, *mb.DeliverySlots, time.Time) {
+	slots, monday := slotSchedule(t)
+	return setupTestDB(t, crtCustomerOrderTbl, crtDeliverySlotTbl, crtSlotBookingTbl), slots, monday
+}
+
+func slotSchedule(t *testing.T) (*mb.DeliverySlots, time.Time) {
+	slots, err := mb.ParseDeliverySlots([]byte(deliverySlotsYAML), "yaml")
+	assert.NoError(t, err)
+	sast, err := time.LoadLocation("Africa/Johannesburg")
+	assert.NoError(t, err)
+	// Monday the 19th, after the morning slot has started
+	return slots, time.Date(2026, 10, 19, 10, 0, 0, 0, sast)
+}
+
+func slotsConvo(message string, slots *mb.DeliverySlots, at time.Time) *mb.ConversationContext {
+	return &mb.ConversationContext{
+		UserInfo:     mb.UserInfo{CellNumber: "0766140000"},
+		UserExisted:  true,
+		Pricelist:    mb.Pricelist{Catalogue: selections, CatalogueID: catalogueID},
+		CurrentOrder: mb.CustomerOrder{OrderID: 1, CellNumber: "0766140000"},
+		MessageBody:  message,
+		DBReadTime:   at,
+		Slots:        slots,
+	}
+}
+
+func Test_DeliverySlotBooking(t *testing.T) {
+	db, slots, monday := setupSlots(t)
+
+	upcoming := slots.Upcoming(monday)
+	assert.Len(t, upcoming, 2)
+	assert.Equal(t, "Mon 19 Oct 14:00-17:00", upcoming[0].Label())
+	assert.Equal(t, "Tue 20 Oct 09:00-12:00", upcoming[1].Label())
+
+	response := mb.GetResponseToMsg(slotsConvo("update order 9:1", slots, monday), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "successfully updated current order", response)
+	response = mb.GetResponseToMsg(slotsConvo("checkoutnow?", slots, monday), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Please choose a delivery slot, type & send-: slots?", response)
+
+	response = mb.GetResponseToMsg(slotsConvo("book slot 3", slots, monday), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Sorry, there is no delivery slot 3. To see the slots type & send-: slots?", response)
+
+	response = mb.GetResponseToMsg(slotsConvo("book slot 1", slots, monday), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Delivery slot Mon 19 Oct 14:00-17:00 booked for your order.", response)
+
+	expected := "Delivery slots:\n\n1. Mon 19 Oct 14:00-17:00 - FULL\n2. Tue 20 Oct 09:00-12:00, 2 left\n\nTo book a slot type & send-: book slot 1"
+	response = mb.GetResponseToMsg(slotsConvo("slots?", slots, monday), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, expected, response)
+
+	// Moving to another slot frees the first
+	response = mb.GetResponseToMsg(slotsConvo("book slot 2", slots, monday), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Delivery slot Tue 20 Oct 09:00-12:00 booked for your order.", response)
+	counts, err := mb.GetSlotBookingCountsFromDB(db, monday)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, counts[upcoming[0].Start.Unix()])
+	assert.Equal(t, 1, counts[upcoming[1].Start.Unix()])
+
+	// Failing to move to a full slot keeps the order in the slot it had
+	other := mb.CustomerOrder{OrderID: 2}
+	assert.NoError(t, other.BookSlot(db, upcoming[0], monday))
+	response = mb.GetResponseToMsg(slotsConvo("book slot 1", slots, monday), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Sorry, that delivery slot is full. To see the slots type & send-: slots?", response)
+	assert.NoError(t, other.ReleaseSlot(db))
+
+	response = mb.GetResponseToMsg(slotsConvo("checkoutnow?", slots, monday), db, mb.CheckoutInfo{}, true)
+	assert.Contains(t, response, "Delivery slot: Tue 20 Oct 09:00-12:00\n")
+
+	bookings, err := mb.GetSlotBookingsFromDB(db, monday, monday.AddDate(0, 0, 2))
+	assert.NoError(t, err)
+	assert.Equal(t, "Tue 20 Oct 09:00-12:00:\nOrder 1 - 0766140000", mb.GetSlotBookingsAsAString(bookings, slots.Location))
+
+	var order mb.CustomerOrder
+	assert.NoError(t, order.SetCurrentOrderFromDB(db, "0766140000", true))
+	assert.NoError(t, order.ReleaseSlot(db))
+	bookings, err = mb.GetSlotBookingsFromDB(db, monday, monday.AddDate(0, 0, 2))
+	assert.NoError(t, err)
+	assert.Empty(t, bookings)
+}
+
+func Test_DeliverySlotCapacity(t *testing.T) {
+	slots, monday := slotSchedule(t)
+	db := setupSharedTestDB(t, crtCustomerOrderTbl, crtDeliverySlotTbl, crtSlotBookingTbl)
+
+	slot := slots.Upcoming(monday)[1]
+	var wg sync.WaitGroup
+	results := make([]error, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(orderID int) {
+			defer wg.Done()
+			order := mb.CustomerOrder{OrderID: orderID}
+			results[orderID-1] = order.BookSlot(db, slot, monday)
+		}(i + 1)
+	}
+	wg.Wait()
+
+	booked := 0
+	for _, err := range results {
+		if err == nil {
+			booked++
+			continue
+		}
+		assert.ErrorIs(t, err, mb.ErrSlotFull)
+	}
+	assert.Equal(t, slot.Capacity, booked)
+
+	counts, err := mb.GetSlotBookingCountsFromDB(db, monday)
+	assert.NoError(t, err)
+	assert.Equal(t, slot.Capacity, counts[slot.Start.Unix()])
+}
@@ -0,0 +1,49 @@
+package menubotlib_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FuzzyMatchCommand(t *testing.T) {
+	tests := []struct {
+		messageBody         string
+		expectedCorrected   string
+		expectedSuggestions []string
+	}{
+		{messageBody: "menu", expectedCorrected: "menu?"},
+		{messageBody: "checkout now?", expectedCorrected: "checkoutnow?"},
+		{messageBody: "Current Order please", expectedCorrected: "currentorder? please"},
+		{messageBody: "updte order 9:12", expectedCorrected: "update order 9:12"},
+		{messageBody: "update socal: @pig", expectedCorrected: "update social: @pig"},
+		{messageBody: "chckoutnw", expectedSuggestions: []string{"checkoutnow?"}},
+		{messageBody: "hello there", expectedCorrected: ""},
+	}
+
+	for _, test := range tests {
+		corrected, suggestions := mb.FuzzyMatchCommand(test.messageBody, mb.DefaultFuzzyMatchConfig)
+		if corrected != test.expectedCorrected || !reflect.DeepEqual(suggestions, test.expectedSuggestions) {
+			t.Errorf("FuzzyMatchCommand(%q) = %q, %v, want %q, %v", test.messageBody, corrected, suggestions, test.expectedCorrected, test.expectedSuggestions)
+		}
+	}
+}
+
+func Test_DidYouMeanResponse(t *testing.T) {
+	convo := &mb.ConversationContext{
+		UserExisted: true,
+		MessageBody: "chckoutnw",
+		FuzzyMatch:  &mb.FuzzyMatchConfig{AutoAcceptDistance: 0, SuggestDistance: 3, MaxSuggestions: 3},
+	}
+
+	response := mb.GetResponseToMsg(convo, nil, mb.CheckoutInfo{}, true)
+	assert.True(t, strings.HasPrefix(response, "Did you mean checkoutnow?"), response)
+
+	convo.MessageBody = "menu"
+	response = mb.GetResponseToMsg(convo, nil, mb.CheckoutInfo{}, true)
+	assert.True(t, strings.HasPrefix(response, "Main Menu, command list:"), response)
+	assert.Equal(t, "menu?", convo.MessageBody)
+}
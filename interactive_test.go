@@ -0,0 +1,100 @@
+package menubotlib_test
+
+import (
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+type textOnlySender struct {
+	sent []string
+}
+
+func (s *textOnlySender) SendText(to, body string) error {
+	s.sent = append(s.sent, body)
+	return nil
+}
+
+type interactiveSender struct {
+	textOnlySender
+	interactive []mb.OutboundMessage
+}
+
+func (s *interactiveSender) SendInteractive(to string, msg mb.OutboundMessage) error {
+	s.interactive = append(s.interactive, msg)
+	return nil
+}
+
+func Test_NewPricelistMessage(t *testing.T) {
+	msg := mb.NewPricelistMessage("price list text", selections)
+
+	assert.True(t, msg.IsInteractive())
+	assert.Equal(t, "price list text", msg.Text)
+	assert.Equal(t, grdngSlctnPreamble, msg.Sections[0].Title)
+
+	rowCount := 0
+	for _, section := range msg.Sections {
+		for _, row := range section.Rows {
+			assert.LessOrEqual(t, len([]rune(row.Title)), 24)
+			rowCount++
+		}
+	}
+	assert.Equal(t, 10, rowCount)
+
+	commandText, ok := mb.CommandFromReplyID(msg.Sections[0].Rows[0].ID)
+	assert.True(t, ok)
+	assert.Equal(t, "item 1?", commandText)
+}
+
+func Test_CommandFromReplyID(t *testing.T) {
+	tests := []struct {
+		replyID     string
+		expected    string
+		expectFound bool
+	}{
+		{replyID: mb.CommandReplyID("checkoutnow?"), expected: "checkoutnow?", expectFound: true},
+		{replyID: "cmd:", expected: "", expectFound: false},
+		{replyID: "checkoutnow?", expected: "", expectFound: false},
+	}
+
+	for _, test := range tests {
+		result, found := mb.CommandFromReplyID(test.replyID)
+		if result != test.expected || found != test.expectFound {
+			t.Errorf("CommandFromReplyID(%q) = %q, %v, want %q, %v", test.replyID, result, found, test.expected, test.expectFound)
+		}
+	}
+}
+
+func Test_SendMessageFallsBackToText(t *testing.T) {
+	msg := mb.NewConfirmMessage("Your order", "checkoutnow?")
+
+	plain := &textOnlySender{}
+	err := mb.SendMessage(plain, "0000000000", msg)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Your order"}, plain.sent)
+
+	rich := &interactiveSender{}
+	err = mb.SendMessage(rich, "0000000000", msg)
+	assert.NoError(t, err)
+	assert.Empty(t, rich.sent)
+	assert.Len(t, rich.interactive, 1)
+	assert.Len(t, rich.interactive[0].Buttons, 2)
+}
+
+func Test_ConfirmCancel(t *testing.T) {
+	msg := mb.NewConfirmMessage("Your order", "checkoutnow?")
+	assert.Equal(t, mb.CancelReplyID, msg.Buttons[1].ID)
+
+	// Cancel runs no command, the order isn't touched and the menu isn't sent
+	convo := &mb.ConversationContext{
+		UserInfo:     mb.UserInfo{CellNumber: "0766140000"},
+		UserExisted:  true,
+		Pricelist:    mb.Pricelist{Catalogue: selections, CatalogueID: catalogueID},
+		CurrentOrder: mb.CustomerOrder{OrderID: 1, CellNumber: "0766140000"},
+		MessageBody:  msg.Buttons[1].ID,
+	}
+	reply := mb.GetInteractiveResponseToMsg(convo, nil, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Okay, nothing has been changed. For a command list please type & send-: menu?", reply.Text)
+	assert.False(t, reply.IsInteractive())
+}
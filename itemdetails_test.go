@@ -0,0 +1,88 @@
+package menubotlib_test
+
+import (
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+// selectionsWithDetails copies the test catalogue with details on the edibles
+func selectionsWithDetails() []mb.CatalogueSelection {
+	edibles := mb.CatalogueSelection{Preamble: EdiblesSelection.Preamble}
+	for _, item := range EdiblesSelection.Items {
+		switch item.CatalogueItemID {
+		case 10:
+			item.Description = "Chewy toffees in mixed fruit flavours."
+			item.Tags = []string{"vegan", "chewy"}
+			item.Attributes = map[string]string{"strength": "400mg", "allergens": "none"}
+		case 11:
+			item.Tags = []string{"sour"}
+			item.Attributes = map[string]string{"strength": "400mg", "allergens": "gluten"}
+		}
+		edibles.Items = append(edibles.Items, item)
+	}
+	return []mb.CatalogueSelection{GardeningSelection, KitchenSelection, DIYSelection, TechSelection, edibles}
+}
+
+func Test_ItemDetailsFromDB(t *testing.T) {
+	db := setupTestDB(t, crtCatalogueItemTbl, crtCatalogueVersionTbl, crtCatalogueSelectionTbl)
+
+	withDetails := selectionsWithDetails()
+	assert.NoError(t, mb.InsertCatalogueItems(db, withDetails))
+
+	prlst, err := mb.LoadPricelistFromDB(db, catalogueID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, withDetails, prlst.Catalogue)
+
+	// Importing the same details again changes nothing
+	diff, err := mb.ImportCatalogue(db, catalogueID, 1, withDetails, true)
+	assert.NoError(t, err)
+	assert.True(t, diff.IsEmpty())
+}
+
+func Test_ItemDetailsExportParse(t *testing.T) {
+	withDetails := selectionsWithDetails()
+	for _, format := range []string{"csv", "json", "yaml"} {
+		data, err := mb.ExportCatalogue(withDetails, format)
+		assert.NoError(t, err, format)
+
+		parsed, err := mb.ParseCatalogue(data, format, catalogueID)
+		assert.NoError(t, err, format)
+		assert.Equal(t, withDetails, parsed, format)
+	}
+
+	_, err := mb.ParseCatalogue([]byte("selection,itemID,item,pricingType,options,mediaRef,mediaCaption,description,tags,attributes\nTech:,8,Apple @ R100,SingleItem,,,,,,strength\n"), "csv", catalogueID)
+	assert.ErrorContains(t, err, `catalogue line 2: attribute "strength" is not in the format name=value`)
+}
+
+func Test_ItemDetailsReplyAndSearch(t *testing.T) {
+	withDetails := selectionsWithDetails()
+
+	expected := "10: Fruit toffees - 400mg\n   1. 10-Pack @ R200\n\n" +
+		"Chewy toffees in mixed fruit flavours.\nallergens: none\nstrength: 400mg\nTags: vegan, chewy\n\n" +
+		"To order please type & send-: update order 10:newAmount"
+	assert.Equal(t, expected, mb.GetItemAsAString(10, withDetails))
+	// Items without details are shown as before
+	assert.Equal(t, "12: Space bud treats - 240mg\n   1. 3-Pack @ R200\n\nTo order please type & send-: update order 12:newAmount", mb.GetItemAsAString(12, withDetails))
+
+	tests := []struct {
+		query   string
+		itemIDs []int
+	}{
+		{"chewy", []int{10}},
+		{"mixed flavour", []int{10}},
+		{"tag:vegan", []int{10}},
+		{"strength:400mg", []int{10, 11}},
+		{"space strength:400mg", []int{11}},
+		{"allergens:none toffee", []int{10}},
+		{"tag:gluten", nil},
+	}
+	for _, test := range tests {
+		var itemIDs []int
+		for _, item := range mb.SearchCatalogue(test.query, withDetails) {
+			itemIDs = append(itemIDs, item.CatalogueItemID)
+		}
+		assert.Equal(t, test.itemIDs, itemIDs, test.query)
+	}
+}
@@ -0,0 +1,100 @@
+package menubotlib_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExpandCommandAliases(t *testing.T) {
+	tests := []struct {
+		messageBody string
+		expected    string
+	}{
+		{messageBody: "bestelling?", expected: "currentorder?"},
+		{messageBody: "opdateer bestelling 9:12 en opdateer taal: af", expected: "update order 9:12 en update language: af"},
+		{messageBody: "imenyu?", expected: "menu?"},
+		{messageBody: "menu?", expected: "menu?"},
+	}
+
+	for _, test := range tests {
+		result := mb.ExpandCommandAliases(test.messageBody, mb.BuiltinTranslations())
+		if result != test.expected {
+			t.Errorf("ExpandCommandAliases(%q) = %q, want %q", test.messageBody, result, test.expected)
+		}
+	}
+}
+
+func Test_ExpandCommandAliasesOtherBundles(t *testing.T) {
+	assert.Equal(t, "currentorder?", mb.ExpandCommandAliases("bestelling?", mb.BuiltinTranslations()))
+
+	// Other bundles aren't answered with the aliases of the bundles before them
+	nl, err := mb.ParseTranslation([]byte(`aliases: {"bestelling bekijken?": "currentorder?"}`), "yaml", mb.DefaultMessages())
+	assert.NoError(t, err)
+	shop := map[string]*mb.Messages{"nl": nl}
+	assert.Equal(t, "currentorder?", mb.ExpandCommandAliases("bestelling bekijken?", shop))
+	assert.Equal(t, "bestelling?", mb.ExpandCommandAliases("bestelling?", shop))
+	assert.Equal(t, "bestelling?", mb.ExpandCommandAliases("bestelling?", map[string]*mb.Messages{}))
+	assert.Equal(t, "currentorder?", mb.ExpandCommandAliases("bestelling?", mb.BuiltinTranslations()))
+}
+
+func Test_LocalisedResponse(t *testing.T) {
+	convo := &mb.ConversationContext{
+		UserInfo: mb.UserInfo{
+			CellNumber: "0766140000",
+			Locale:     mb.NullString{NullString: sql.NullString{String: "af", Valid: true}},
+		},
+		UserExisted: true,
+		MessageBody: "hallo daar",
+	}
+
+	response := mb.GetResponseToMsg(convo, nil, mb.CheckoutInfo{}, true)
+	assert.True(t, strings.HasPrefix(response, "Err:NC, Jammer"), response)
+	assert.Contains(t, response, "spyskaart?")
+
+	// The main menu is not translated so falls back to English
+	convo.MessageBody = "Spyskaart?"
+	response = mb.GetResponseToMsg(convo, nil, mb.CheckoutInfo{}, true)
+	assert.True(t, strings.HasPrefix(response, "Main Menu, command list:"), response)
+
+	// Messages the translation leaves out come from the shop's copy before the built in copy
+	shopMessages, err := mb.ParseMessages([]byte("mainMenu: Flying Rasta menu"), "yaml")
+	assert.NoError(t, err)
+	convo.Messages = shopMessages
+	response = mb.GetResponseToMsg(convo, nil, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Flying Rasta menu", response)
+	convo.Messages = nil
+
+	convo.UserInfo.Locale = mb.NullString{}
+	convo.MessageBody = "hello"
+	response = mb.GetResponseToMsg(convo, nil, mb.CheckoutInfo{}, true)
+	assert.True(t, strings.HasPrefix(response, "Err:NC, Sorry"), response)
+}
+
+func Test_MigrateUserInfo(t *testing.T) {
+	// A userinfo table from before users had a language, catalogue or address
+	db := setupTestDB(t, `
+	CREATE TABLE userinfo (
+		cellnumber varchar(15) PRIMARY KEY,
+		nickname varchar(255),
+		email varchar(255),
+		socialmedia varchar(255),
+		consent BOOLEAN,
+		datetimejoined DATETIME
+	);`)
+	_, err := db.Exec(`INSERT INTO userinfo (cellnumber, nickname) VALUES ('0766140000', 'Pig')`)
+	assert.NoError(t, err)
+
+	ui := mb.UserInfo{CellNumber: "0766140000"}
+	assert.Error(t, ui.SetUserInfoFromDB(db))
+
+	// Migrating again leaves the columns alone
+	assert.NoError(t, mb.MigrateUserInfo(db))
+	assert.NoError(t, mb.MigrateUserInfo(db))
+	assert.NoError(t, ui.SetUserInfoFromDB(db))
+	assert.Equal(t, "Pig", ui.NickName.String)
+	assert.Equal(t, mb.DefaultLocale, ui.GetLocale())
+}
@@ -20,16 +20,49 @@ func Test_ParseUpdateOrderCommand(t *testing.T) {
 		{
 			commandText: "update order 6:0",
 			expected: []mb.MenuIndication{
-				{ItemMenuNum: 6, ItemAmount: "0"},
+				{ItemMenuNum: 6, ItemAmount: mb.MustParseQuantity("0")},
 			},
 			expectError: false,
 		},
 		{
 			commandText: "update order 9:12, 10: 1x3, 3x2, 2x1, 6:5",
 			expected: []mb.MenuIndication{
-				{ItemMenuNum: 10, ItemAmount: "1x3, 3x2, 2x1"},
-				{ItemMenuNum: 9, ItemAmount: "12"},
-				{ItemMenuNum: 6, ItemAmount: "5"},
+				{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("12")},
+				{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x3, 3x2, 2x1")},
+				{ItemMenuNum: 6, ItemAmount: mb.MustParseQuantity("5")},
+			},
+			expectError: false,
+		},
+		{
+			commandText: "update order 9:+2; 6:-1\n10:1 x 3,3x2",
+			expected: []mb.MenuIndication{
+				{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("+2")},
+				{ItemMenuNum: 6, ItemAmount: mb.MustParseQuantity("-1")},
+				{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x3, 3x2")},
+			},
+			expectError: false,
+		},
+		{
+			commandText: "update order 9:12\nupdate email: pig@example.com",
+			expected: []mb.MenuIndication{
+				{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("12")},
+			},
+			expectError: false,
+		},
+		{
+			commandText: "update order 9:12 thanks!",
+			expected:    nil,
+			expectError: true,
+		},
+		{
+			commandText: "update order 9:12g",
+			expected:    nil,
+			expectError: true,
+		},
+		{
+			commandText: "update order 10:+1x3",
+			expected: []mb.MenuIndication{
+				{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("+1x3")},
 			},
 			expectError: false,
 		},
@@ -61,28 +94,28 @@ func Test_UpdateCustOrdItems(t *testing.T) {
 			given: mb.CustomerOrder{
 				OrderItems: mb.OrderItems{
 					MenuIndications: []mb.MenuIndication{
-						{ItemMenuNum: 10, ItemAmount: "1x3"},
-						{ItemMenuNum: 9, ItemAmount: "5"},
-						{ItemMenuNum: 8, ItemAmount: "6"},
-						{ItemMenuNum: 6, ItemAmount: "5"},
-						{ItemMenuNum: 5, ItemAmount: "9"},
+						{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x3")},
+						{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("5")},
+						{ItemMenuNum: 8, ItemAmount: mb.MustParseQuantity("6")},
+						{ItemMenuNum: 6, ItemAmount: mb.MustParseQuantity("5")},
+						{ItemMenuNum: 5, ItemAmount: mb.MustParseQuantity("9")},
 					},
 				},
 			},
 			update: mb.OrderItems{
 				MenuIndications: []mb.MenuIndication{
-					{ItemMenuNum: 10, ItemAmount: "1x3, 3x2, 2x1"},
-					{ItemMenuNum: 6, ItemAmount: "0"},
-					{ItemMenuNum: 7, ItemAmount: "7"},
+					{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x3, 3x2, 2x1")},
+					{ItemMenuNum: 6, ItemAmount: mb.MustParseQuantity("0")},
+					{ItemMenuNum: 7, ItemAmount: mb.MustParseQuantity("7")},
 				},
 			},
 			expected: mb.OrderItems{
 				MenuIndications: []mb.MenuIndication{
-					{ItemMenuNum: 10, ItemAmount: "1x3, 3x2, 2x1"},
-					{ItemMenuNum: 9, ItemAmount: "5"},
-					{ItemMenuNum: 8, ItemAmount: "6"},
-					{ItemMenuNum: 5, ItemAmount: "9"},
-					{ItemMenuNum: 7, ItemAmount: "7"},
+					{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x3, 3x2, 2x1")},
+					{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("5")},
+					{ItemMenuNum: 8, ItemAmount: mb.MustParseQuantity("6")},
+					{ItemMenuNum: 5, ItemAmount: mb.MustParseQuantity("9")},
+					{ItemMenuNum: 7, ItemAmount: mb.MustParseQuantity("7")},
 				},
 			},
 			expectError: false,
@@ -123,17 +156,17 @@ func Test_NewOrder_UpdateOrInsertCurrentOrder(t *testing.T) {
 				CatalogueID: catalogueID,
 				OrderItems: mb.OrderItems{
 					MenuIndications: []mb.MenuIndication{
-						{ItemMenuNum: 10, ItemAmount: "1x3, 3x2, 2x1"},
-						{ItemMenuNum: 9, ItemAmount: "12"},
-						{ItemMenuNum: 6, ItemAmount: "5"},
+						{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x3, 3x2, 2x1")},
+						{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("12")},
+						{ItemMenuNum: 6, ItemAmount: mb.MustParseQuantity("5")},
 					},
 				},
 			},
 			expected: mb.OrderItems{
 				MenuIndications: []mb.MenuIndication{
-					{ItemMenuNum: 10, ItemAmount: "1x3, 3x2, 2x1"},
-					{ItemMenuNum: 9, ItemAmount: "12"},
-					{ItemMenuNum: 6, ItemAmount: "5"},
+					{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x3, 3x2, 2x1")},
+					{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("12")},
+					{ItemMenuNum: 6, ItemAmount: mb.MustParseQuantity("5")},
 				},
 			},
 			expectError: false,
@@ -218,17 +251,17 @@ func Test_CheckoutNow(t *testing.T) {
 				CatalogueID: catalogueID,
 				OrderItems: mb.OrderItems{
 					MenuIndications: []mb.MenuIndication{
-						{ItemMenuNum: 10, ItemAmount: "1x3, 3x2, 2x1"},
-						{ItemMenuNum: 9, ItemAmount: "12"},
-						{ItemMenuNum: 6, ItemAmount: "5"},
+						{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x3, 3x2, 2x1")},
+						{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("12")},
+						{ItemMenuNum: 6, ItemAmount: mb.MustParseQuantity("5")},
 					},
 				},
 			},
 			expected: mb.OrderItems{
 				MenuIndications: []mb.MenuIndication{
-					{ItemMenuNum: 10, ItemAmount: "1x3, 3x2, 2x1"},
-					{ItemMenuNum: 9, ItemAmount: "12"},
-					{ItemMenuNum: 6, ItemAmount: "5"},
+					{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x3, 3x2, 2x1")},
+					{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("12")},
+					{ItemMenuNum: 6, ItemAmount: mb.MustParseQuantity("5")},
 				},
 			},
 			expectError: false,
@@ -264,6 +297,9 @@ func Test_PrintPriceList(t *testing.T) {
 	_, err = db.Exec(crtCatalogueItemTbl)
 	assert.NoError(t, err)
 
+	_, err = db.Exec(crtCatalogueVersionTbl)
+	assert.NoError(t, err)
+
 	err = mb.InsertCatalogueItems(db, selections)
 	assert.NoError(t, err)
 
@@ -285,6 +321,9 @@ func Test_ComposedCatalogueSelections(t *testing.T) {
 	_, err = db.Exec(crtCatalogueItemTbl)
 	assert.NoError(t, err)
 
+	_, err = db.Exec(crtCatalogueVersionTbl)
+	assert.NoError(t, err)
+
 	err = mb.InsertCatalogueItems(db, selections)
 	assert.NoError(t, err)
 
@@ -303,7 +342,7 @@ func Test_CalculatePriceWithNoOptions(t *testing.T) {
 	// Denitrified fertilizer:
 	//  - "5g @ R110 p.g.",
 	//  - "10g @ R90 p.g.",
-	// Therefore Expected Total: 12 * 90 = 1080
+	// Therefore Expected Total: 12 * 90 = R1080, in cents
 	tests := []struct {
 		ordItems      mb.OrderItems
 		expctdTotal   int
@@ -313,10 +352,10 @@ func Test_CalculatePriceWithNoOptions(t *testing.T) {
 		{
 			ordItems: mb.OrderItems{
 				MenuIndications: []mb.MenuIndication{
-					{ItemMenuNum: 1, ItemAmount: "12"},
+					{ItemMenuNum: 1, ItemAmount: mb.MustParseQuantity("12")},
 				},
 			},
-			expctdTotal:   1080,
+			expctdTotal:   108000,
 			expctdSummary: "",
 			expctError:    false,
 		},
@@ -0,0 +1,102 @@
+package menubotlib_test
+
+import (
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+type mediaSender struct {
+	textOnlySender
+	media    []mb.Media
+	captions []string
+}
+
+func (s *mediaSender) SendMedia(to string, media mb.Media, caption string) error {
+	s.media = append(s.media, media)
+	s.captions = append(s.captions, caption)
+	return nil
+}
+
+var broomMedia = mb.Media{Ref: "https://example.com/broom.jpg", Caption: "Sweeps without bristles."}
+
+// selectionsWithMedia copies the test catalogue with a picture of the broom and of the DIY selection
+func selectionsWithMedia() []mb.CatalogueSelection {
+	diy := mb.CatalogueSelection{Preamble: DIYSelection.Preamble, Media: mb.Media{Ref: "/srv/media/diy.png"}}
+	for _, item := range DIYSelection.Items {
+		item.Media = broomMedia
+		diy.Items = append(diy.Items, item)
+	}
+	return []mb.CatalogueSelection{GardeningSelection, KitchenSelection, diy, TechSelection, EdiblesSelection}
+}
+
+func Test_CatalogueMediaFromDB(t *testing.T) {
+	db := setupTestDB(t, crtCatalogueItemTbl, crtCatalogueVersionTbl, crtCatalogueSelectionTbl)
+
+	withMedia := selectionsWithMedia()
+	assert.NoError(t, mb.InsertCatalogueItems(db, withMedia))
+	assert.NoError(t, mb.SetSelectionMedia(db, catalogueID, diySlctnPreamble, mb.Media{Ref: "/srv/media/diy.png"}))
+
+	prlst, err := mb.LoadPricelistFromDB(db, catalogueID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, withMedia, prlst.Catalogue)
+
+	// Drafts keep the pictures of the live version
+	draft, err := mb.CreateDraftCatalogueVersion(db, catalogueID)
+	assert.NoError(t, err)
+	items, err := mb.GetCatalogueVersionItemsFromDB(db, catalogueID, draft)
+	assert.NoError(t, err)
+	assert.Equal(t, broomMedia, items[6].Media)
+
+	assert.NoError(t, mb.SetSelectionMedia(db, catalogueID, diySlctnPreamble, mb.Media{}))
+	selectionMedia, err := mb.GetSelectionMediaFromDB(db, catalogueID)
+	assert.NoError(t, err)
+	assert.Empty(t, selectionMedia)
+}
+
+func Test_CatalogueMediaExportParse(t *testing.T) {
+	withMedia := selectionsWithMedia()
+	withMedia[2].Media = mb.Media{}
+	for _, format := range []string{"csv", "json", "yaml"} {
+		data, err := mb.ExportCatalogue(withMedia, format)
+		assert.NoError(t, err, format)
+
+		parsed, err := mb.ParseCatalogue(data, format, catalogueID)
+		assert.NoError(t, err, format)
+		assert.Equal(t, withMedia, parsed, format)
+	}
+}
+
+func Test_ItemMediaMessage(t *testing.T) {
+	convo := &mb.ConversationContext{
+		UserInfo:    mb.UserInfo{CellNumber: "0766140000"},
+		UserExisted: true,
+		Pricelist:   mb.Pricelist{Catalogue: selectionsWithMedia(), CatalogueID: catalogueID},
+		MessageBody: "item 7?",
+	}
+	msg := mb.GetInteractiveResponseToMsg(convo, nil, mb.CheckoutInfo{}, true)
+	assert.Equal(t, broomMedia, msg.Media)
+	assert.Contains(t, msg.Body, "Sweeps without bristles.\n\n7: Bristleless Broom\n")
+
+	media := &mediaSender{}
+	assert.NoError(t, mb.SendMessage(media, "0000000000", msg))
+	assert.Empty(t, media.sent)
+	assert.Equal(t, []mb.Media{broomMedia}, media.media)
+	assert.Equal(t, []string{msg.Body}, media.captions)
+
+	// Without media the reply links to the picture
+	plain := &textOnlySender{}
+	assert.NoError(t, mb.SendMessage(plain, "0000000000", msg))
+	assert.Equal(t, []string{msg.Body + "\n\nPicture: https://example.com/broom.jpg"}, plain.sent)
+
+	// Selections send their picture with the section
+	convo.MessageBody = "prlist diy?"
+	msg = mb.GetInteractiveResponseToMsg(convo, nil, mb.CheckoutInfo{}, true)
+	assert.Equal(t, mb.Media{Ref: "/srv/media/diy.png"}, msg.Media)
+	assert.NotContains(t, msg.Text, "Picture:")
+
+	convo.MessageBody = "item 8?"
+	msg = mb.GetInteractiveResponseToMsg(convo, nil, mb.CheckoutInfo{}, true)
+	assert.False(t, msg.HasMedia())
+}
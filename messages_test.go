@@ -0,0 +1,67 @@
+package menubotlib_test
+
+import (
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseMessages(t *testing.T) {
+	yamlBundle := []byte(`
+coldGreeting: "Welcome to Pig's Shop, {{.User.CellNumber}}."
+pricelistPreamble: "{{len .Catalogue}} sections on offer."
+`)
+
+	msgs, err := mb.ParseMessages(yamlBundle, "yaml")
+	assert.NoError(t, err)
+
+	data := mb.MessageData{
+		User:      mb.UserInfo{CellNumber: "0766140000"},
+		Catalogue: selections,
+	}
+
+	assert.Equal(t, "Welcome to Pig's Shop, 0766140000.", msgs.Render(mb.MsgColdGreeting, data))
+	assert.Equal(t, "5 sections on offer.", msgs.Render(mb.MsgPricelistPreamble, data))
+	// Messages not in the bundle keep their default
+	assert.Equal(t, mb.DefaultMessages().Render(mb.MsgSayMenu, data), msgs.Render(mb.MsgSayMenu, data))
+
+	jsonBundle := []byte(`{"noCommand": "Sorry {{.User.NickName.String}}, no command found."}`)
+	msgs, err = mb.ParseMessages(jsonBundle, "json")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sorry , no command found.", msgs.Render(mb.MsgNoCommand, data))
+}
+
+func Test_ParseMessagesChecksFieldsWithoutRunning(t *testing.T) {
+	// Indexing names and reading the order fail on empty data but are valid copy
+	bundle := []byte(`
+unavailable: "Sorry, {{index .Names 0}} isn't available on order {{.Order.OrderID}}."
+stockShortfall: "{{range $s := .Shortfalls}}{{$s.Item}}: {{.Left}} {{end}}"
+`)
+	msgs, err := mb.ParseMessages(bundle, "yaml")
+	assert.NoError(t, err)
+	data := mb.MessageData{Names: []string{"Broom"}, Order: mb.CustomerOrder{OrderID: 7}}
+	assert.Equal(t, "Sorry, Broom isn't available on order 7.", msgs.Render(mb.MsgUnavailable, data))
+}
+
+func Test_ParseMessagesInvalidTemplate(t *testing.T) {
+	tests := []struct {
+		bundle string
+		format string
+	}{
+		{bundle: `mainMenu: "{{.User.CellNumber"`, format: "yaml"},
+		{bundle: `{"sayMenu": "{{if}}"}`, format: "json"},
+		// Unknown fields are found when the bundle loads, in ranges too
+		{bundle: `coldGreeting: "Hello {{.User.Nmae}}"`, format: "yaml"},
+		{bundle: `stockShortfall: "{{range .Shortfalls}}{{.Itme}}{{end}}"`, format: "yaml"},
+		{bundle: `pickupCode: "{{$.Order.Nope}}"`, format: "yaml"},
+		{bundle: `sayMenu = "menu?"`, format: "toml"},
+	}
+
+	for _, test := range tests {
+		_, err := mb.ParseMessages([]byte(test.bundle), test.format)
+		if err == nil {
+			t.Errorf("ParseMessages(%q, %s) expected an error", test.bundle, test.format)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package menubotlib_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseNaturalLanguageOrder(t *testing.T) {
+	tests := []struct {
+		text                   string
+		expected               []mb.MenuIndication
+		expectedClarifications int
+	}{
+		{
+			text: "2 fruit toffees and 12g fertilizer",
+			expected: []mb.MenuIndication{
+				{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x2")},
+				{ItemMenuNum: 1, ItemAmount: mb.MustParseQuantity("12")},
+			},
+		},
+		{
+			text: "3 packs of sour space strips, 12 grams of item 1",
+			expected: []mb.MenuIndication{
+				{ItemMenuNum: 11, ItemAmount: mb.MustParseQuantity("1x3")},
+				{ItemMenuNum: 1, ItemAmount: mb.MustParseQuantity("12")},
+			},
+		},
+		{
+			text: "a bristled handleless broom and two fertiliser",
+			expected: []mb.MenuIndication{
+				{ItemMenuNum: 7, ItemAmount: mb.MustParseQuantity("2x1")},
+				{ItemMenuNum: 1, ItemAmount: mb.MustParseQuantity("2")},
+			},
+		},
+		{
+			text:                   "2 space things",
+			expected:               nil,
+			expectedClarifications: 1,
+		},
+		{
+			text:                   "1 broom",
+			expected:               nil,
+			expectedClarifications: 1,
+		},
+	}
+
+	for _, test := range tests {
+		result := mb.ParseNaturalLanguageOrder(test.text, selections)
+		if !reflect.DeepEqual(result.MenuIndications, test.expected) {
+			t.Errorf("ParseNaturalLanguageOrder(%q) = %v, want %v", test.text, result.MenuIndications, test.expected)
+		}
+		if len(result.Clarifications) != test.expectedClarifications {
+			t.Errorf("ParseNaturalLanguageOrder(%q) clarifications = %v, want %d", test.text, result.Clarifications, test.expectedClarifications)
+		}
+	}
+}
+
+func Test_NaturalOrderReply(t *testing.T) {
+	order := mb.ParseNaturalLanguageOrder("2 fruit toffees and 12g fertilizer", selections)
+	reply := order.GetNaturalOrderReply(selections)
+
+	assert.Contains(t, reply, "2 x Fruit toffees - 400mg, 10-Pack @ R200")
+	assert.Contains(t, reply, "12g of Denitrified fertilizer")
+	assert.True(t, strings.HasSuffix(reply, "update order 10:1x2, 1:12"), reply)
+
+	// The echoed command is understood by the update order parser
+	parsed, err := mb.ParseUpdateOrderCommand(order.AsUpdateOrderCommand())
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, order.MenuIndications, parsed)
+}
+
+func Test_NaturalOrderReplyMessages(t *testing.T) {
+	msgs, err := mb.ParseMessages([]byte(`
+orderUnderstood: "Got it:\n{{.List}}\nSend {{.Command}}"
+weightLine: "{{.Name}}, {{.Quantity}} grams"
+optionLine: "{{.Name}} ({{.Option}}) x{{.Number}}"
+itemNotFound: "No {{.Name}} here."
+`), "yaml")
+	assert.NoError(t, err)
+	convo := &mb.ConversationContext{
+		UserInfo:    mb.UserInfo{CellNumber: "0766140000"},
+		UserExisted: true,
+		Pricelist:   mb.Pricelist{Catalogue: selections, CatalogueID: catalogueID},
+		Messages:    msgs,
+		MessageBody: "order 2 fruit toffees, 12g fertilizer and a spaceship",
+	}
+	reply := mb.GetResponseToMsg(convo, nil, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Got it:\nFruit toffees - 400mg (10-Pack @ R200) x2\nDenitrified fertilizer, 12 grams\nSend update order 10:1x2, 1:12\n\nNo spaceship here.", reply)
+}
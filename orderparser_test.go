@@ -0,0 +1,184 @@
+package menubotlib_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseUpdateOrderCommandErrors(t *testing.T) {
+	tests := []struct {
+		commandText string
+		expectedPos int
+		expectedMsg string
+	}{
+		{
+			commandText: "update order 9:12g",
+			expectedPos: 4,
+			expectedMsg: "couldn't read '12g' after item 9, did you mean 9:12?",
+		},
+		{
+			commandText: "update order 9:12 thanks!",
+			expectedPos: 7,
+			expectedMsg: "couldn't read 'thanks!' after item 9:12, items must be separated by ',' ';' or a new line",
+		},
+		{
+			commandText: "update order 9 12",
+			expectedPos: 4,
+			expectedMsg: "expected ':' after item 9 but found '12', did you mean 9:12?",
+		},
+		{
+			commandText: "update order 10:1x0, 2x0",
+			expectedPos: 7,
+			expectedMsg: "option 1 of item 10 has an amount of 0, leave the option out or remove the item with 10:0",
+		},
+		{
+			commandText: "update order",
+			expectedPos: 1,
+			expectedMsg: "no items found, please use the format-: update order 1:newAmount, 3:newAmount",
+		},
+	}
+
+	for _, test := range tests {
+		_, err := mb.ParseUpdateOrderCommand(test.commandText)
+		var syntaxErr *mb.OrderSyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("ParseUpdateOrderCommand(%q) error = %v, want an OrderSyntaxError", test.commandText, err)
+			continue
+		}
+		assert.Equal(t, test.expectedPos, syntaxErr.Pos, test.commandText)
+		assert.Equal(t, test.expectedMsg, syntaxErr.Msg, test.commandText)
+	}
+}
+
+func Test_UpdateCustOrdItemsRelative(t *testing.T) {
+	custOrd := mb.CustomerOrder{
+		OrderItems: mb.OrderItems{
+			MenuIndications: []mb.MenuIndication{
+				{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("5")},
+				{ItemMenuNum: 6, ItemAmount: mb.MustParseQuantity("2")},
+				{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x3")},
+			},
+		},
+	}
+
+	err := custOrd.UpdateCustOrdItems(mb.OrderItems{
+		MenuIndications: []mb.MenuIndication{
+			{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("+2")},
+			{ItemMenuNum: 6, ItemAmount: mb.MustParseQuantity("-5")},
+			{ItemMenuNum: 1, ItemAmount: mb.MustParseQuantity("+12")},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []mb.MenuIndication{
+		{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("7")},
+		{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x3")},
+		{ItemMenuNum: 1, ItemAmount: mb.MustParseQuantity("12")},
+	}, custOrd.OrderItems.MenuIndications)
+
+	err = custOrd.UpdateCustOrdItems(mb.OrderItems{
+		MenuIndications: []mb.MenuIndication{{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("+1")}},
+	})
+	assert.Error(t, err)
+}
+
+func FuzzParseUpdateOrderCommand(f *testing.F) {
+	f.Add("update order 6:0")
+	f.Add("update order 9:12, 10: 1x3, 3x2, 2x1, 6:5")
+	f.Add("update order: 9:+2; 6:-1\n10:1 x 3")
+	f.Add("update order 9:12 thanks!")
+	f.Add("update order 9:12g")
+	f.Add("update order 10:1x")
+
+	f.Fuzz(func(t *testing.T, commandText string) {
+		result, err := mb.ParseUpdateOrderCommand(commandText)
+		if err != nil {
+			var syntaxErr *mb.OrderSyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("ParseUpdateOrderCommand(%q) error = %v, want an OrderSyntaxError", commandText, err)
+			}
+			if syntaxErr.Pos < 1 || syntaxErr.Pos > len([]rune(commandText))+1 {
+				t.Fatalf("ParseUpdateOrderCommand(%q) error position %d out of range", commandText, syntaxErr.Pos)
+			}
+			return
+		}
+
+		// Whatever was read must read back the same from its canonical form
+		var parts []string
+		for _, mi := range result {
+			parts = append(parts, fmt.Sprintf("%d:%s", mi.ItemMenuNum, mi.ItemAmount))
+		}
+		reparsed, err := mb.ParseUpdateOrderCommand("update order " + strings.Join(parts, "; "))
+		if err != nil {
+			t.Fatalf("canonical form of %q failed to parse: %v", commandText, err)
+		}
+		assert.Equal(t, result, reparsed)
+	})
+}
+
+func Test_AdjustCustOrdItems(t *testing.T) {
+	custOrd := mb.CustomerOrder{
+		OrderItems: mb.OrderItems{
+			MenuIndications: []mb.MenuIndication{
+				{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x3, 3x2, 2x1")},
+				{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("12")},
+			},
+		},
+	}
+
+	adjustments, err := mb.ParseAdjustOrderCommand("add 10: 2x1, 4x1; 9:3\n11:1x1")
+	assert.NoError(t, err)
+	err = custOrd.AdjustCustOrdItems(mb.OrderItems{MenuIndications: adjustments}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []mb.MenuIndication{
+		{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x3, 2x2, 3x2, 4x1")},
+		{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("15")},
+		{ItemMenuNum: 11, ItemAmount: mb.MustParseQuantity("1x1")},
+	}, custOrd.OrderItems.MenuIndications)
+
+	adjustments, err = mb.ParseAdjustOrderCommand("remove 10: 2x2, 1x5; 11:1x1; 12:1x1")
+	assert.NoError(t, err)
+	err = custOrd.AdjustCustOrdItems(mb.OrderItems{MenuIndications: adjustments}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []mb.MenuIndication{
+		{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("3x2, 4x1")},
+		{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("15")},
+	}, custOrd.OrderItems.MenuIndications)
+
+	// Relative update order amounts share the same merge
+	err = custOrd.UpdateCustOrdItems(mb.OrderItems{MenuIndications: []mb.MenuIndication{{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("+1x1")}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "1x1, 3x2, 4x1", custOrd.OrderItems.MenuIndications[0].ItemAmount.String())
+
+	_, err = mb.ParseAdjustOrderCommand("add 9:+3")
+	assert.Error(t, err)
+	err = custOrd.AdjustCustOrdItems(mb.OrderItems{MenuIndications: []mb.MenuIndication{{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("1x1")}}}, false)
+	assert.Error(t, err)
+}
+
+func Test_AdjustOrderCommandMatching(t *testing.T) {
+	convo := &mb.ConversationContext{UserInfo: mb.UserInfo{CellNumber: "0766140000"}}
+	tests := []struct {
+		message  string
+		expected []string
+	}{
+		{message: "add 10: 2x1", expected: []string{"10: 2x1"}},
+		{message: "Remove:11:1x1\nadd 9:3", expected: []string{"11:1x1\nadd 9:3", "9:3"}},
+		// Lines starting with the words but no item number aren't commands
+		{message: "add me to the list\nremove my email please", expected: nil},
+	}
+
+	for _, test := range tests {
+		var texts []string
+		for _, command := range mb.GetCommandsFromLastMessage(test.message, convo, nil, mb.CheckoutInfo{}, true) {
+			if adjust, ok := command.(mb.AdjustOrderCommand); ok {
+				texts = append(texts, adjust.Text)
+			}
+		}
+		assert.Equal(t, test.expected, texts, test.message)
+	}
+}
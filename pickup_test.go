@@ -0,0 +1,102 @@
+package menubotlib_test
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+const pickupLocationsYAML = `
+locations:
+  - name: Gardens shop
+    address: 1 Kloof Street, Gardens
+    hours: Mon-Fri 08:00-17:00
+  - name: Harbour kiosk
+    address: V&A Waterfront
+`
+
+func pickupConvo(t *testing.T, message string, zones *mb.DeliveryZones) *mb.ConversationContext {
+	locations, err := mb.ParsePickupLocations([]byte(pickupLocationsYAML), "yaml")
+	assert.NoError(t, err)
+	return &mb.ConversationContext{
+		UserInfo:        mb.UserInfo{CellNumber: "0766140000"},
+		UserExisted:     true,
+		Pricelist:       mb.Pricelist{Catalogue: selections, CatalogueID: catalogueID},
+		CurrentOrder:    mb.CustomerOrder{OrderID: 1, CellNumber: "0766140000"},
+		MessageBody:     message,
+		Delivery:        zones,
+		PickupLocations: locations,
+	}
+}
+
+func setupPickup(t *testing.T) *sql.DB {
+	return setupTestDB(t, crtCustomerOrderTbl, crtPickupCodeTbl)
+}
+
+func Test_PickupFulfilment(t *testing.T) {
+	db := setupPickup(t)
+
+	expected := "Pickup locations:\n\n1. Gardens shop, 1 Kloof Street, Gardens (Mon-Fri 08:00-17:00)\n2. Harbour kiosk, V&A Waterfront\n\nTo collect your order type & send-: pickup 1"
+	assert.Equal(t, expected, mb.GetResponseToMsg(pickupConvo(t, "pickup?", nil), db, mb.CheckoutInfo{}, true))
+
+	// A shop without delivery zones needs a pickup location
+	mb.GetResponseToMsg(pickupConvo(t, "update order 9:1", nil), db, mb.CheckoutInfo{}, true)
+	response := mb.GetResponseToMsg(pickupConvo(t, "checkoutnow?", nil), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Please choose where to collect your order, type & send-: pickup?", response)
+
+	response = mb.GetResponseToMsg(pickupConvo(t, "pickup lighthouse", nil), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Sorry, there is no pickup location \"lighthouse\". To see the locations type & send-: pickup?", response)
+
+	response = mb.GetResponseToMsg(pickupConvo(t, "pickup harbour", nil), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Your order will be ready for collection at Harbour kiosk, V&A Waterfront. We'll send you a pickup code once it's paid.", response)
+
+	// Collected orders need no address and pay no delivery fee
+	zones, err := mb.ParseDeliveryZones([]byte(deliveryZonesYAML), "yaml")
+	assert.NoError(t, err)
+	response = mb.GetResponseToMsg(pickupConvo(t, "checkoutnow?", zones), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Collect at: Harbour kiosk\n/n/nCheckout initiation failed", response)
+
+	// Asking for delivery again switches the order back
+	mb.GetResponseToMsg(pickupConvo(t, "deliver to: 12 Long Street, Gardens", zones), db, mb.CheckoutInfo{}, true)
+	response = mb.GetResponseToMsg(pickupConvo(t, "checkoutnow?", zones), db, mb.CheckoutInfo{}, true)
+	assert.Contains(t, response, "Delivery (City Bowl): R30\nTotal: R180\n")
+}
+
+func Test_PickupCode(t *testing.T) {
+	db := setupPickup(t)
+
+	mb.GetResponseToMsg(pickupConvo(t, "update order 9:1", nil), db, mb.CheckoutInfo{}, true)
+	mb.GetResponseToMsg(pickupConvo(t, "pickup 1", nil), db, mb.CheckoutInfo{}, true)
+	var order mb.CustomerOrder
+	assert.NoError(t, order.SetCurrentOrderFromDB(db, "0766140000", true))
+	assert.Equal(t, mb.PickedUp, order.OrderItems.Fulfilment)
+
+	assert.EqualError(t, mb.VerifyPickupCode(db, order.OrderID, "123456"), "order 1 has no pickup code, it may not be paid yet")
+
+	code, err := order.IssuePickupCode(db)
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^\d{6}$`), code)
+	// A repeated payment notification sends the same code
+	again, err := order.IssuePickupCode(db)
+	assert.NoError(t, err)
+	assert.Equal(t, code, again)
+
+	locations, err := mb.ParsePickupLocations([]byte(pickupLocationsYAML), "yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "Thank you, order 1 is paid. Collect it at Gardens shop, 1 Kloof Street, Gardens (Mon-Fri 08:00-17:00) and show pickup code "+code+".",
+		order.PickupCodeMessage(code, locations, nil))
+
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	assert.ErrorIs(t, mb.VerifyPickupCode(db, order.OrderID, wrong), mb.ErrPickupCodeMismatch)
+	assert.NoError(t, mb.VerifyPickupCode(db, order.OrderID, code))
+	assert.ErrorContains(t, mb.VerifyPickupCode(db, order.OrderID, code), "order 1 was already collected at")
+
+	assert.NoError(t, order.SetCurrentOrderFromDB(db, "0766140000", true))
+	assert.True(t, order.DateTimeDelivered.Valid)
+}
@@ -0,0 +1,179 @@
+package menubotlib_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+const specialsSlctnPreamble = "Specials:"
+
+var specialsSelections = []mb.CatalogueSelection{
+	{
+		Preamble: specialsSlctnPreamble,
+		Items: []mb.CatalogueItem{
+			{CatalogueID: catalogueID, CatalogueItemID: 13, Selection: specialsSlctnPreamble, Item: "Cold brew",
+				Options: []string{"100ml @ R2 p.ml.", "500ml @ R1 p.ml."}, PricingType: mb.VolumeItem},
+			{CatalogueID: catalogueID, CatalogueItemID: 14, Selection: specialsSlctnPreamble, Item: "Sparkling water",
+				Options: []string{"6-Pack @ R100", "10-Pack @ R150", "1-Can @ R20"}, PricingType: mb.PackItem},
+			{CatalogueID: catalogueID, CatalogueItemID: 15, Selection: specialsSlctnPreamble, Item: "Wedding cake", PricingType: mb.PriceOnRequest},
+			{CatalogueID: catalogueID, CatalogueItemID: 16, Selection: specialsSlctnPreamble, Item: "Boxed eggs",
+				Options: []string{"6-Box @ R40", "12-Box @ R70"}, PricingType: mb.PackItem},
+		},
+	},
+}
+
+// dozenPricer prices an item by the dozen in cents, rounding up, as a shop registering its own pricing type would
+type dozenPricer struct{}
+
+func (dozenPricer) Price(line mb.OrderLine) (mb.LinePrice, error) {
+	var perDozen int
+	if _, err := fmt.Sscanf(line.Item.Options[0], "R%d a dozen", &perDozen); err != nil {
+		return mb.LinePrice{}, err
+	}
+	dozens := (line.Amount.Weight + 11) / 12
+	return mb.LinePrice{Total: dozens * perDozen * 100}, nil
+}
+
+func (dozenPricer) Validate(item mb.CatalogueItem) []string {
+	if len(item.Options) != 1 {
+		return []string{"must have one price a dozen"}
+	}
+	return nil
+}
+
+func Test_RegisteredPricers(t *testing.T) {
+	assert.NoError(t, mb.ValidateCatalogue(specialsSelections))
+
+	tests := []struct {
+		name          string
+		amounts       map[int]string
+		expctdTotal   int
+		expctdSummary string
+	}{
+		{"volume below the threshold", map[int]string{13: "250"}, 50000, ""},
+		{"volume at the threshold", map[int]string{13: "500"}, 50000, ""},
+		{"cheapest packs", map[int]string{14: "16"}, 25000, ""},
+		{"packs and cans", map[int]string{14: "13"}, 21000, ""},
+		{"sizes that can't be made up", map[int]string{16: "8"}, 0,
+			"while tallying the order, error extracting the order item price: item 16 comes in packs of 6, 12, 8 units can't be made up of them"},
+		{"price on request", map[int]string{13: "100", 15: "1"}, 20000, "Item 15 is priced on request, the shop will send you a quote\n"},
+	}
+
+	for _, test := range tests {
+		var ordItems mb.OrderItems
+		for _, itemMenuNum := range []int{13, 14, 15, 16} {
+			if amount, ok := test.amounts[itemMenuNum]; ok {
+				ordItems.MenuIndications = append(ordItems.MenuIndications, mb.MenuIndication{ItemMenuNum: itemMenuNum, ItemAmount: mb.MustParseQuantity(amount)})
+			}
+		}
+		total, summary := ordItems.CalculatePrice(specialsSelections)
+		assert.Equal(t, test.expctdTotal, total, test.name)
+		assert.Equal(t, test.expctdSummary, summary, test.name)
+	}
+
+	// Shops register their own pricing types without changing CalculatePrice
+	eggs := []mb.CatalogueSelection{{Preamble: specialsSlctnPreamble, Items: []mb.CatalogueItem{
+		{CatalogueItemID: 17, Item: "Free range eggs", Options: []string{"R45 a dozen"}, PricingType: "DozenItem"},
+	}}}
+	assert.ErrorContains(t, mb.ValidateCatalogue(eggs), `item 17 (Free range eggs) has unknown pricing type "DozenItem"`)
+
+	mb.RegisterPricer("DozenItem", dozenPricer{})
+	assert.NoError(t, mb.ValidateCatalogue(eggs))
+	ordItems := mb.OrderItems{MenuIndications: []mb.MenuIndication{{ItemMenuNum: 17, ItemAmount: mb.MustParseQuantity("18")}}}
+	total, summary := ordItems.CalculatePrice(eggs)
+	assert.Equal(t, 9000, total)
+	assert.Empty(t, summary)
+}
+
+func Test_PricesInCents(t *testing.T) {
+	syrups := []mb.CatalogueSelection{{Preamble: specialsSlctnPreamble, Items: []mb.CatalogueItem{
+		{CatalogueItemID: 18, Item: "Vanilla syrup", Options: []string{"100ml @ R0.35 p.ml.", "500ml @ R0.3 p.ml."}, PricingType: mb.VolumeItem},
+		{CatalogueItemID: 19, Item: "Syrup pump @ R12.50 each, 3+ @ R9.99", PricingType: mb.SingleItem},
+	}}}
+	assert.NoError(t, mb.ValidateCatalogue(syrups))
+
+	ordItems := mb.OrderItems{MenuIndications: []mb.MenuIndication{
+		{ItemMenuNum: 18, ItemAmount: mb.MustParseQuantity("250")},
+		{ItemMenuNum: 19, ItemAmount: mb.MustParseQuantity("2")},
+	}}
+	total, summary := ordItems.CalculatePrice(syrups)
+	assert.Equal(t, 250*35+2*1250, total)
+	assert.Empty(t, summary)
+
+	// Larger amounts reach the cheaper tiers
+	ordItems.MenuIndications[0].ItemAmount = mb.MustParseQuantity("500")
+	ordItems.MenuIndications[1].ItemAmount = mb.MustParseQuantity("3")
+	total, _ = ordItems.CalculatePrice(syrups)
+	assert.Equal(t, 500*30+3*999, total)
+
+	assert.Contains(t, mb.GetPricelistIndexAsAString(syrups), "1. Specials - 2 item(s), R0.30 to R12.50\n")
+
+	tooPrecise := []mb.CatalogueSelection{{Preamble: specialsSlctnPreamble, Items: []mb.CatalogueItem{
+		{CatalogueItemID: 18, Item: "Vanilla syrup", Options: []string{"100ml @ R0.355 p.ml."}, PricingType: mb.VolumeItem},
+		{CatalogueItemID: 19, Item: "Syrup pump @ R12.50 each, 3+ @ R9.999", PricingType: mb.SingleItem},
+	}}}
+	var report *mb.CatalogueValidationError
+	assert.ErrorAs(t, mb.ValidateCatalogue(tooPrecise), &report)
+	assert.Equal(t, []string{
+		`item 18 (Vanilla syrup) option 1 "100ml @ R0.355 p.ml." is not in the format 100ml @ R2`,
+		`item 19 (Syrup pump @ R12.50 each, 3+ @ R9.999) has no options and no price in its name`,
+	}, report.Problems)
+}
+
+func Test_ValidateRegisteredPricers(t *testing.T) {
+	invalid := []mb.CatalogueSelection{
+		{
+			Preamble: specialsSlctnPreamble,
+			Items: []mb.CatalogueItem{
+				{CatalogueItemID: 13, Item: "Cold brew", Options: []string{"500ml @ R1 p.ml.", "100ml @ R2 p.ml."}, PricingType: mb.VolumeItem},
+				{CatalogueItemID: 14, Item: "Sparkling water", PricingType: mb.PackItem},
+				{CatalogueItemID: 16, Item: "Boxed eggs", Options: []string{"6-Box @ R40", "Half dozen @ R40", "6-Tray @ R45"}, PricingType: mb.PackItem},
+			},
+		},
+	}
+
+	err := mb.ValidateCatalogue(invalid)
+	var report *mb.CatalogueValidationError
+	assert.ErrorAs(t, err, &report)
+	assert.Equal(t, []string{
+		`item 13 (Cold brew) option 2 "100ml @ R2 p.ml." must be a larger volume than the option before it`,
+		`item 14 (Sparkling water) is a PackItem without any pack sizes`,
+		`item 16 (Boxed eggs) option 2 "Half dozen @ R40" is not in the format 6-Pack @ R100`,
+		`item 16 (Boxed eggs) option 3 "6-Tray @ R45" has the same pack size as an option before it`,
+	}, report.Problems)
+}
+
+func Test_PriceOnRequestCheckout(t *testing.T) {
+	db := setupTestDB(t, crtQuoteRequestTbl)
+
+	requestedAt := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	var requested []mb.QuoteRequest
+	convo := &mb.ConversationContext{
+		UserInfo:    mb.UserInfo{CellNumber: "0766140000"},
+		UserExisted: true,
+		Pricelist:   mb.Pricelist{Catalogue: specialsSelections, CatalogueID: catalogueID},
+		CurrentOrder: mb.CustomerOrder{OrderID: 1, CellNumber: "0766140000", OrderItems: mb.OrderItems{MenuIndications: []mb.MenuIndication{
+			{ItemMenuNum: 13, ItemAmount: mb.MustParseQuantity("100")},
+			{ItemMenuNum: 15, ItemAmount: mb.MustParseQuantity("1")},
+		}}},
+		MessageBody:    "checkoutnow?",
+		DBReadTime:     requestedAt,
+		QuoteRequested: func(request mb.QuoteRequest) { requested = append(requested, request) },
+	}
+
+	response := mb.GetResponseToMsg(convo, db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Item(s) 15 are priced on request. We've asked the shop for a quote and will message you the price before you pay.", response)
+
+	expected := mb.QuoteRequest{OrderID: 1, CellNumber: "0766140000", Items: []mb.MenuIndication{{ItemMenuNum: 15, ItemAmount: mb.MustParseQuantity("1")}}, RequestedAt: requestedAt}
+	assert.Equal(t, []mb.QuoteRequest{expected}, requested)
+	assert.Equal(t, "Quote requested for order 1 by 0766140000:\n1 x Wedding cake", expected.AdminMessage(specialsSelections))
+
+	recorded, err := mb.GetQuoteRequestFromDB(db, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, expected.Items, recorded.Items)
+	assert.True(t, requestedAt.Equal(recorded.RequestedAt))
+}
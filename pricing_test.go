@@ -0,0 +1,100 @@
+package menubotlib_test
+
+import (
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+var tieredSelections = []mb.CatalogueSelection{
+	{
+		Preamble: tchSlctnPreamble,
+		Items: []mb.CatalogueItem{
+			{CatalogueID: catalogueID, CatalogueItemID: 8, Selection: tchSlctnPreamble, Item: "Macless Apple @ R100 each, 10+ @ R90", PricingType: mb.SingleItem},
+		},
+	},
+	{
+		Preamble: edblsSlctnPreamble,
+		Items: []mb.CatalogueItem{
+			{CatalogueID: catalogueID, CatalogueItemID: 10, Selection: edblsSlctnPreamble, Item: "Fruit toffees - 400mg",
+				Options: []string{"10-Pack @ R200, 5+ @ R180, 10+ @ R160"}, PricingType: mb.SingleItem, TierGroup: "packs"},
+			{CatalogueID: catalogueID, CatalogueItemID: 11, Selection: edblsSlctnPreamble, Item: "Sour space strips - 400mg",
+				Options: []string{"10-Pack @ R180, 5+ @ R170"}, PricingType: mb.SingleItem, TierGroup: "packs"},
+			{CatalogueID: catalogueID, CatalogueItemID: 12, Selection: edblsSlctnPreamble, Item: "Space bud treats - 240mg",
+				Options: []string{"3-Pack @ R200, 5+ @ R150", "6-Pack @ R380"}, PricingType: mb.SingleItem},
+		},
+	},
+}
+
+func Test_TieredPricing(t *testing.T) {
+	assert.NoError(t, mb.ValidateCatalogue(tieredSelections))
+
+	tests := []struct {
+		name        string
+		amounts     map[int]string
+		expctdTotal int
+	}{
+		{"below the first break", map[int]string{12: "1x4"}, 80000},
+		{"at the first break", map[int]string{12: "1x5"}, 75000},
+		{"breaks are per option", map[int]string{12: "1x3, 2x3"}, 174000},
+		{"tier groups mix and match", map[int]string{10: "1x3", 11: "1x2"}, (3*180 + 2*170) * 100},
+		{"the best break of the group", map[int]string{10: "1x6", 11: "1x4"}, (6*160 + 4*170) * 100},
+		{"items without options", map[int]string{8: "9"}, 90000},
+		{"items without options at a break", map[int]string{8: "10"}, 90000},
+	}
+
+	for _, test := range tests {
+		var ordItems mb.OrderItems
+		for itemMenuNum, amount := range test.amounts {
+			ordItems.MenuIndications = append(ordItems.MenuIndications, mb.MenuIndication{ItemMenuNum: itemMenuNum, ItemAmount: mb.MustParseQuantity(amount)})
+		}
+		total, summary := ordItems.CalculatePrice(tieredSelections)
+		assert.Equal(t, test.expctdTotal, total, test.name)
+		assert.Empty(t, summary, test.name)
+	}
+
+	// The unchanged catalogue prices option-less items from their name too
+	cellphones := mb.OrderItems{MenuIndications: []mb.MenuIndication{{ItemMenuNum: 9, ItemAmount: mb.MustParseQuantity("12")}}}
+	total, summary := cellphones.CalculatePrice(selections)
+	assert.Equal(t, 180000, total)
+	assert.Empty(t, summary)
+}
+
+func Test_ValidateTieredPricing(t *testing.T) {
+	invalid := []mb.CatalogueSelection{
+		{
+			Preamble: edblsSlctnPreamble,
+			Items: []mb.CatalogueItem{
+				{CatalogueItemID: 10, Item: "Fruit toffees", Options: []string{"10-Pack @ R200, 10+ @ R160, 5+ @ R180"}, PricingType: mb.SingleItem},
+				{CatalogueItemID: 11, Item: "Space strips", Options: []string{"10-Pack @ R180"}, PricingType: mb.SingleItem, TierGroup: "packs"},
+				{CatalogueItemID: 1, Item: "Fertilizer", Options: []string{"5g @ R110 p.g."}, PricingType: mb.WeightItem, TierGroup: "packs"},
+			},
+		},
+	}
+
+	err := mb.ValidateCatalogue(invalid)
+	var report *mb.CatalogueValidationError
+	assert.ErrorAs(t, err, &report)
+	assert.Equal(t, []string{
+		`item 10 (Fruit toffees) option 1 "10-Pack @ R200, 10+ @ R160, 5+ @ R180": price break 5+ must be for more than the one before it`,
+		`item 1 (Fertilizer) is a WeightItem in tier group "packs" of SingleItems`,
+	}, report.Problems)
+}
+
+func Test_TierGroupFromDB(t *testing.T) {
+	db := setupTestDB(t, crtCatalogueItemTbl, crtCatalogueVersionTbl, crtCatalogueSelectionTbl)
+
+	assert.NoError(t, mb.InsertCatalogueItems(db, tieredSelections))
+	prlst, err := mb.LoadPricelistFromDB(db, catalogueID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, tieredSelections, prlst.Catalogue)
+
+	for _, format := range []string{"csv", "json", "yaml"} {
+		data, err := mb.ExportCatalogue(tieredSelections, format)
+		assert.NoError(t, err, format)
+		parsed, err := mb.ParseCatalogue(data, format, catalogueID)
+		assert.NoError(t, err, format)
+		assert.Equal(t, tieredSelections, parsed, format)
+	}
+}
@@ -0,0 +1,49 @@
+package menubotlib_test
+
+import (
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PricelistIndex(t *testing.T) {
+	expected := `Price list sections:
+
+1. Gardening - 3 item(s), R90 to R150
+2. Kitchen - 3 item(s), R160 to R250
+3. DIY - 1 item(s), R650
+4. Tech - 2 item(s), R100 to R150
+5. Edibles - 3 item(s), R180 to R200
+
+To see a section type & send-: prlist 2?
+To see the whole price list type & send-: prlist all?`
+	assert.Equal(t, expected, mb.GetPricelistIndexAsAString(selections))
+}
+
+func Test_PricelistSectionCommand(t *testing.T) {
+	db := setupAvailability(t)
+
+	// Item 8 is hidden, so it's left out of the index and its section
+	response := mb.GetResponseToMsg(availabilityConvo("fr.prlist?"), db, mb.CheckoutInfo{}, true)
+	assert.Contains(t, response, "4. Tech - 1 item(s), R150\n")
+
+	tests := []struct {
+		message  string
+		expected string
+	}{
+		{"prlist 4?", "Tech:\n9: Unchargeable cellphone @ R150 each\n\n\nTo order please type & send-: update order itemNumber:newAmount"},
+		{"prlist tech?", "Tech:\n9: Unchargeable cellphone @ R150 each\n\n\nTo order please type & send-: update order itemNumber:newAmount"},
+		{"Prlist Te?", "Tech:\n9: Unchargeable cellphone @ R150 each\n\n\nTo order please type & send-: update order itemNumber:newAmount"},
+		{"prlist 9?", "Sorry, there is no price list section \"9\". To see the sections type & send-: fr.prlist?"},
+		{"prlist toys?", "Sorry, there is no price list section \"toys\". To see the sections type & send-: fr.prlist?"},
+	}
+	for _, test := range tests {
+		response = mb.GetResponseToMsg(availabilityConvo(test.message), db, mb.CheckoutInfo{}, true)
+		assert.Equal(t, test.expected, response, test.message)
+	}
+
+	response = mb.GetResponseToMsg(availabilityConvo("prlist all?"), db, mb.CheckoutInfo{}, true)
+	assert.Contains(t, response, "Kitchen:\n4: DIY ready cake mix")
+	assert.Contains(t, response, "Edibles:\n10: Fruit toffees - 400mg")
+}
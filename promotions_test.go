@@ -0,0 +1,205 @@
+package menubotlib_test
+
+import (
+	"testing"
+	"time"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+const promotionsYAML = `
+promotions:
+  - code: WEEKEND10
+    description: 10% off this weekend
+    type: percentage
+    percent: 10
+    minSpend: 300
+    maxUsesPerUser: 1
+    validFrom: 2026-10-17T00:00:00+02:00
+    validUntil: 2026-10-19T00:00:00+02:00
+  - description: Buy 2 toffee packs, get 1 free
+    type: buyXGetY
+    items: [10]
+    buy: 2
+    free: 1
+  - description: Toffee and strips bundle
+    type: bundle
+    items: [10, 11]
+    price: 350
+  - code: BROOM100
+    description: R100 off brooms
+    type: fixed
+    amount: 100
+    items: [7]
+`
+
+func Test_ParsePromotions(t *testing.T) {
+	promotions, err := mb.ParsePromotions([]byte(promotionsYAML), "yaml")
+	assert.NoError(t, err)
+	assert.Len(t, promotions, 4)
+	assert.Equal(t, mb.BuyXGetY, promotions[1].Type)
+
+	sast, err := time.LoadLocation("Africa/Johannesburg")
+	assert.NoError(t, err)
+	assert.True(t, promotions[0].IsValidAt(time.Date(2026, 10, 18, 12, 0, 0, 0, sast)))
+	assert.False(t, promotions[0].IsValidAt(time.Date(2026, 10, 19, 0, 0, 0, 0, sast)))
+	assert.True(t, promotions[1].IsValidAt(time.Now()))
+
+	tests := []struct {
+		data     string
+		expected string
+	}{
+		{`{"promotions": [{"code": "X", "type": "percentage", "percent": 120}]}`, "promotion 1 (X): percent should be between 1 and 100"},
+		{`{"promotions": [{"description": "Two for one", "type": "bundle", "items": [10], "price": 100}]}`, "promotion 1 (Two for one): a bundle needs at least 2 items"},
+		{`{"promotions": [{"code": "X", "type": "fixed", "amount": 5}, {"code": "x", "type": "fixed", "amount": 10}]}`, "promotion 2 (x): the code x is used more than once"},
+		{`{"promotions": [{"code": "X", "type": "freebie"}]}`, "promotion 1 (X): unknown promotion type: freebie"},
+	}
+	for _, test := range tests {
+		_, err := mb.ParsePromotions([]byte(test.data), "json")
+		assert.EqualError(t, err, test.expected)
+	}
+}
+
+func Test_ValidatePromotions(t *testing.T) {
+	promotions, err := mb.ParsePromotions([]byte(promotionsYAML), "yaml")
+	assert.NoError(t, err)
+	assert.NoError(t, mb.ValidatePromotions(promotions, selections))
+
+	tests := []struct {
+		data     string
+		expected string
+	}{
+		{`{"promotions": [{"description": "Buy 10g get 5g free", "type": "buyXGetY", "items": [1], "buy": 2, "free": 1}]}`, "promotion 1 (Buy 10g get 5g free): item 1 is a WeightItem, buyXGetY promotions only apply to items ordered by the unit"},
+		{`{"promotions": [{"description": "Garden bundle", "type": "bundle", "items": [3, 9], "price": 100}]}`, "promotion 1 (Garden bundle): item 3 is a WeightItem, bundle promotions only apply to items ordered by the unit"},
+		{`{"promotions": [{"code": "X", "type": "fixed", "amount": 5, "items": [99]}]}`, "promotion 1 (X): item 99 is not in the catalogue"},
+	}
+	for _, test := range tests {
+		promotions, err := mb.ParsePromotions([]byte(test.data), "json")
+		assert.NoError(t, err)
+		assert.EqualError(t, mb.ValidatePromotions(promotions, selections), test.expected)
+	}
+
+	// Percentages and fixed amounts apply to what measure items cost
+	promotions, err = mb.ParsePromotions([]byte(`{"promotions": [{"code": "X", "type": "percentage", "percent": 10, "items": [1]}]}`), "json")
+	assert.NoError(t, err)
+	assert.NoError(t, mb.ValidatePromotions(promotions, selections))
+}
+
+func Test_CalculatePriceWithPromotions(t *testing.T) {
+	promotions, err := mb.ParsePromotions([]byte(promotionsYAML), "yaml")
+	assert.NoError(t, err)
+
+	options := func(amounts map[int]int) mb.Quantity { return mb.Quantity{Options: amounts} }
+	tests := []struct {
+		name          string
+		ordItems      mb.OrderItems
+		expctdTotal   int
+		expctdSummary string
+	}{
+		{
+			name:          "no promotion applies",
+			ordItems:      mb.OrderItems{MenuIndications: []mb.MenuIndication{{ItemMenuNum: 12, ItemAmount: options(map[int]int{1: 1})}}},
+			expctdTotal:   20000,
+			expctdSummary: "",
+		},
+		{
+			name:          "buy 2 get 1 free",
+			ordItems:      mb.OrderItems{MenuIndications: []mb.MenuIndication{{ItemMenuNum: 10, ItemAmount: options(map[int]int{1: 3})}}},
+			expctdTotal:   40000,
+			expctdSummary: "Subtotal: R600\nBuy 2 toffee packs, get 1 free: -R200\nTotal: R400\n",
+		},
+		{
+			name: "code and bundle",
+			ordItems: mb.OrderItems{
+				MenuIndications: []mb.MenuIndication{
+					{ItemMenuNum: 10, ItemAmount: options(map[int]int{1: 1})},
+					{ItemMenuNum: 11, ItemAmount: options(map[int]int{1: 1})},
+				},
+				Codes: []string{"WEEKEND10"},
+			},
+			expctdTotal:   31200,
+			expctdSummary: "Subtotal: R380\n10% off this weekend: -R38\nToffee and strips bundle: -R30\nTotal: R312\n",
+		},
+		{
+			name: "minimum spend not reached",
+			ordItems: mb.OrderItems{
+				MenuIndications: []mb.MenuIndication{{ItemMenuNum: 12, ItemAmount: options(map[int]int{1: 1})}},
+				Codes:           []string{"WEEKEND10"},
+			},
+			expctdTotal:   20000,
+			expctdSummary: "",
+		},
+		{
+			name: "fixed discount on an item",
+			ordItems: mb.OrderItems{
+				MenuIndications: []mb.MenuIndication{
+					{ItemMenuNum: 7, ItemAmount: options(map[int]int{1: 1})},
+					{ItemMenuNum: 12, ItemAmount: options(map[int]int{1: 1})},
+				},
+				Codes: []string{"BROOM100"},
+			},
+			expctdTotal:   75000,
+			expctdSummary: "Subtotal: R850\nR100 off brooms: -R100\nTotal: R750\n",
+		},
+	}
+
+	for _, test := range tests {
+		total, summary := test.ordItems.CalculatePrice(selections, promotions...)
+		assert.Equal(t, test.expctdTotal, total, test.name)
+		assert.Equal(t, test.expctdSummary, summary, test.name)
+	}
+}
+
+func Test_ApplyCodeCommand(t *testing.T) {
+	db := setupTestDB(t, crtCustomerOrderTbl, crtPromotionUseTbl)
+
+	promotions, err := mb.ParsePromotions([]byte(promotionsYAML), "yaml")
+	assert.NoError(t, err)
+	sast, err := time.LoadLocation("Africa/Johannesburg")
+	assert.NoError(t, err)
+
+	convo := func(message string, orderID int, at time.Time) *mb.ConversationContext {
+		return &mb.ConversationContext{
+			UserInfo:     mb.UserInfo{CellNumber: "0766140000"},
+			UserExisted:  true,
+			Pricelist:    mb.Pricelist{Catalogue: selections, CatalogueID: catalogueID},
+			CurrentOrder: mb.CustomerOrder{OrderID: orderID, CellNumber: "0766140000"},
+			MessageBody:  message,
+			DBReadTime:   at,
+			Promotions:   promotions,
+		}
+	}
+	saturday := time.Date(2026, 10, 17, 10, 0, 0, 0, sast)
+
+	response := mb.GetResponseToMsg(convo("apply code nope", 1, saturday), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Sorry, NOPE isn't a valid code.", response)
+
+	response = mb.GetResponseToMsg(convo("apply code weekend10", 1, saturday.AddDate(0, 0, 7)), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Sorry, code WEEKEND10 isn't valid at the moment.", response)
+
+	response = mb.GetResponseToMsg(convo("apply code weekend10", 1, saturday), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Code WEEKEND10 applied: 10% off this weekend. It takes effect on orders of R300 or more.", response)
+
+	var order mb.CustomerOrder
+	assert.NoError(t, order.SetCurrentOrderFromDB(db, "0766140000", true))
+	assert.Equal(t, []string{"WEEKEND10"}, order.OrderItems.Codes)
+
+	// Checking out doesn't use the code up, paying for the order does
+	mb.GetResponseToMsg(convo("checkoutnow?", 1, saturday), db, mb.CheckoutInfo{}, true)
+	uses, err := mb.CountPromotionUses(db, "WEEKEND10", "0766140000", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, uses)
+
+	assert.NoError(t, order.RecordPromotionUses(db, saturday, promotions...))
+	assert.NoError(t, order.RecordPromotionUses(db, saturday, promotions...))
+	response = mb.GetResponseToMsg(convo("apply code weekend10", 2, saturday), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Sorry, you've already used code WEEKEND10.", response)
+
+	uses, err = mb.CountPromotionUses(db, "WEEKEND10", "0766140000", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, uses)
+	uses, err = mb.CountPromotionUses(db, "WEEKEND10", "0766140000", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, uses)
+}
@@ -0,0 +1,50 @@
+package menubotlib_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_OrderItemsLegacyJSON(t *testing.T) {
+	legacy := `{"MenuIndications":[{"ItemMenuNum":9,"ItemAmount":"12"},{"ItemMenuNum":10,"ItemAmount":"1x3, 3x2, 2x1, 1x1"},{"ItemMenuNum":6,"ItemAmount":"5"}]}`
+
+	var orderItems mb.OrderItems
+	err := json.Unmarshal([]byte(legacy), &orderItems)
+	assert.NoError(t, err)
+	assert.Equal(t, mb.OrderItems{
+		MenuIndications: []mb.MenuIndication{
+			{ItemMenuNum: 9, ItemAmount: mb.WeightQuantity(12)},
+			{ItemMenuNum: 10, ItemAmount: mb.Quantity{Options: map[int]int{1: 4, 2: 1, 3: 2}}},
+			{ItemMenuNum: 6, ItemAmount: mb.WeightQuantity(5)},
+		},
+	}, orderItems)
+
+	typed, err := json.Marshal(orderItems)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"MenuIndications":[{"ItemMenuNum":9,"ItemAmount":{"Weight":12}},{"ItemMenuNum":10,"ItemAmount":{"Options":{"1":4,"2":1,"3":2}}},{"ItemMenuNum":6,"ItemAmount":{"Weight":5}}]}`, string(typed))
+
+	var roundTrip mb.OrderItems
+	err = json.Unmarshal(typed, &roundTrip)
+	assert.NoError(t, err)
+	assert.Equal(t, orderItems, roundTrip)
+}
+
+func Test_CleanOrderItemsRemovesZeroLines(t *testing.T) {
+	var orderItems mb.OrderItems
+	err := json.Unmarshal([]byte(`{"MenuIndications":[{"ItemMenuNum":1,"ItemAmount":"00"},{"ItemMenuNum":10,"ItemAmount":"0x3"},{"ItemMenuNum":11,"ItemAmount":"1x0"},{"ItemMenuNum":12,"ItemAmount":"1x2"}]}`), &orderItems)
+	assert.NoError(t, err)
+
+	custOrd := mb.CustomerOrder{OrderItems: orderItems}
+	err = custOrd.UpdateCustOrdItems(mb.OrderItems{
+		MenuIndications: []mb.MenuIndication{
+			{ItemMenuNum: 12, ItemAmount: mb.MustParseQuantity("+1x1, 2x0")},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []mb.MenuIndication{
+		{ItemMenuNum: 12, ItemAmount: mb.MustParseQuantity("1x3")},
+	}, custOrd.OrderItems.MenuIndications)
+}
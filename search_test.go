@@ -0,0 +1,45 @@
+package menubotlib_test
+
+import (
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SearchCatalogue(t *testing.T) {
+	tests := []struct {
+		query   string
+		itemIDs []int
+	}{
+		{"toffee", []int{10}},
+		{"Tôffées", []int{10}},
+		{"space", []int{11, 12}},
+		{"edibles", []int{10, 11, 12}},
+		{"the space strips", []int{11}},
+		{"handleless", []int{7}},
+		{"cellphones", []int{9}},
+		{"fertiliser", []int{1}},
+		{"space fertilizer", nil},
+		{"the", nil},
+	}
+
+	for _, test := range tests {
+		var itemIDs []int
+		for _, item := range mb.SearchCatalogue(test.query, selections) {
+			itemIDs = append(itemIDs, item.CatalogueItemID)
+		}
+		assert.Equal(t, test.itemIDs, itemIDs, test.query)
+	}
+}
+
+func Test_SearchCommand(t *testing.T) {
+	db := setupAvailability(t)
+
+	response := mb.GetResponseToMsg(availabilityConvo("search toffee"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Found 1 item(s) matching \"toffee\":\n\n10: Fruit toffees - 400mg\n   1. 10-Pack @ R200\n\nTo order please type & send-: update order itemNumber:newAmount", response)
+
+	// Hidden items aren't found
+	response = mb.GetResponseToMsg(availabilityConvo("find apple"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Sorry, nothing on the price list matches \"apple\". To see the price list type & send-: fr.prlist?", response)
+}
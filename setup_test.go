@@ -2,8 +2,11 @@ package menubotlib_test
 
 import (
 	"database/sql"
+	"path/filepath"
+	"testing"
 
 	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
 	_ "modernc.org/sqlite"
 )
 
@@ -27,18 +30,140 @@ const (
 		orderTotal INTEGER DEFAULT 0,
 		ispaid BOOLEAN DEFAULT 0,
 		datetimedelivered DATETIME,
-		isclosed BOOLEAN DEFAULT 0
+		isclosed BOOLEAN DEFAULT 0,
+		catalogueversion INTEGER DEFAULT 1
 	);`
 
 	crtCatalogueItemTbl = `
 	CREATE TABLE catalogueitem (
 		catalogueID varchar(255) NOT NULL,
+		version INTEGER NOT NULL DEFAULT 1,
 		catalogueitemID INTEGER NOT NULL,
 		"selection" varchar(255) NULL,
 		"item" varchar(255) NULL,
 		"options" varchar(255) NULL,
 		pricingType pricingTypeEnum,
-		CONSTRAINT catalogueitem_pk PRIMARY KEY (catalogueID, catalogueitemID)
+		mediaref varchar(1024) NULL,
+		mediacaption varchar(1024) NULL,
+		description varchar(1024) NULL,
+		tags varchar(255) NULL,
+		attributes varchar(1024) NULL,
+		tiergroup varchar(255) NULL,
+		CONSTRAINT catalogueitem_pk PRIMARY KEY (catalogueID, version, catalogueitemID)
+	);`
+
+	crtUserInfoTbl = `
+	CREATE TABLE userinfo (
+		cellnumber varchar(15) PRIMARY KEY,
+		nickname varchar(255),
+		email varchar(255),
+		socialmedia varchar(255),
+		consent BOOLEAN,
+		datetimejoined DATETIME,
+		locale varchar(10),
+		catalogueID varchar(255),
+		address varchar(255)
+	);`
+
+	crtCatalogueStockTbl = `
+	CREATE TABLE cataloguestock (
+		catalogueID varchar(255) NOT NULL,
+		catalogueitemID INTEGER NOT NULL,
+		optionnum INTEGER NOT NULL DEFAULT 0,
+		onhand INTEGER NOT NULL DEFAULT 0,
+		reserved INTEGER NOT NULL DEFAULT 0,
+		CONSTRAINT cataloguestock_pk PRIMARY KEY (catalogueID, catalogueitemID, optionnum)
+	);`
+
+	crtStockReservationTbl = `
+	CREATE TABLE stockreservation (
+		orderID INTEGER NOT NULL,
+		catalogueID varchar(255) NOT NULL,
+		catalogueitemID INTEGER NOT NULL,
+		optionnum INTEGER NOT NULL,
+		amount INTEGER NOT NULL,
+		reservedat DATETIME NOT NULL
+	);`
+
+	crtPromotionUseTbl = `
+	CREATE TABLE promotionuse (
+		code varchar(50) NOT NULL,
+		cellnumber varchar(15) NOT NULL,
+		orderID INTEGER NOT NULL,
+		usedat DATETIME NOT NULL,
+		CONSTRAINT promotionuse_pk PRIMARY KEY (code, orderID)
+	);`
+
+	crtQuoteRequestTbl = `
+	CREATE TABLE quoterequest (
+		orderID INTEGER NOT NULL,
+		cellnumber varchar(15) NOT NULL,
+		items TEXT NOT NULL,
+		requestedat DATETIME NOT NULL,
+		CONSTRAINT quoterequest_pk PRIMARY KEY (orderID)
+	);`
+
+	crtDeliverySlotTbl = `
+	CREATE TABLE deliveryslot (
+		slotstart DATETIME NOT NULL,
+		booked INTEGER NOT NULL DEFAULT 0,
+		CONSTRAINT deliveryslot_pk PRIMARY KEY (slotstart)
+	);`
+
+	crtSlotBookingTbl = `
+	CREATE TABLE slotbooking (
+		orderID INTEGER NOT NULL,
+		slotstart DATETIME NOT NULL,
+		slotend DATETIME NOT NULL,
+		bookedat DATETIME NOT NULL,
+		CONSTRAINT slotbooking_pk PRIMARY KEY (orderID)
+	);`
+
+	crtPickupCodeTbl = `
+	CREATE TABLE pickupcode (
+		orderID INTEGER NOT NULL,
+		code varchar(10) NOT NULL,
+		issuedat DATETIME NOT NULL,
+		collectedat DATETIME NULL,
+		CONSTRAINT pickupcode_pk PRIMARY KEY (orderID)
+	);`
+
+	crtTaxInvoiceTbl = `
+	CREATE TABLE taxinvoice (
+		invoiceno INTEGER NOT NULL,
+		orderID INTEGER NOT NULL,
+		issuedat DATETIME NOT NULL,
+		total INTEGER NOT NULL,
+		details TEXT NOT NULL,
+		CONSTRAINT taxinvoice_pk PRIMARY KEY (invoiceno),
+		CONSTRAINT taxinvoice_order UNIQUE (orderID)
+	);`
+
+	crtItemAvailabilityTbl = `
+	CREATE TABLE itemavailability (
+		catalogueID varchar(255) NOT NULL,
+		catalogueitemID INTEGER NOT NULL,
+		optionnum INTEGER NOT NULL DEFAULT 0,
+		available BOOLEAN NOT NULL,
+		CONSTRAINT itemavailability_pk PRIMARY KEY (catalogueID, catalogueitemID, optionnum)
+	);`
+
+	crtCatalogueSelectionTbl = `
+	CREATE TABLE catalogueselection (
+		catalogueID varchar(255) NOT NULL,
+		"selection" varchar(255) NOT NULL,
+		mediaref varchar(1024) NOT NULL,
+		mediacaption varchar(1024) NULL,
+		CONSTRAINT catalogueselection_pk PRIMARY KEY (catalogueID, "selection")
+	);`
+
+	crtCatalogueVersionTbl = `
+	CREATE TABLE catalogueversion (
+		catalogueID varchar(255) NOT NULL,
+		version INTEGER NOT NULL,
+		effectivefrom DATETIME NULL,
+		createdat DATETIME NOT NULL,
+		CONSTRAINT catalogueversion_pk PRIMARY KEY (catalogueID, version)
 	);`
 )
 
@@ -51,6 +176,54 @@ func setupTestDBInstance() (*sql.DB, error) {
 	return db, nil
 }
 
+// setupTestDB opens an in memory database with the tables of the DDL, closed when the test ends
+func setupTestDB(t *testing.T, ddls ...string) *sql.DB {
+	db, err := setupTestDBInstance()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	createTables(t, db, ddls)
+	return db
+}
+
+// setupSharedTestDB opens a database file every connection shares, so goroutines can race on it.
+// Transactions take the write lock when they begin and wait for each other rather than failing.
+func setupSharedTestDB(t *testing.T, ddls ...string) *sql.DB {
+	dsn := "file:" + filepath.Join(t.TempDir(), "shared.db") + "?_txlock=immediate&_pragma=busy_timeout(10000)"
+	db, err := sql.Open("sqlite", dsn)
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	createTables(t, db, ddls)
+	return db
+}
+
+func createTables(t *testing.T, db *sql.DB, ddls []string) {
+	for _, ddl := range ddls {
+		_, err := db.Exec(ddl)
+		assert.NoError(t, err)
+	}
+}
+
+// setupAvailability hides item 8 and option 2 of item 7, the price list, search and availability tests share it
+func setupAvailability(t *testing.T) *sql.DB {
+	db := setupTestDB(t, crtCustomerOrderTbl, crtItemAvailabilityTbl)
+	assert.NoError(t, mb.SetItemAvailability(db, catalogueID, 8, 0, false))
+	assert.NoError(t, mb.SetItemAvailability(db, catalogueID, 7, 2, false))
+	assert.NoError(t, mb.SetItemAvailability(db, catalogueID, 9, 0, false))
+	assert.NoError(t, mb.SetItemAvailability(db, catalogueID, 9, 0, true))
+	return db
+}
+
+func availabilityConvo(message string) *mb.ConversationContext {
+	return &mb.ConversationContext{
+		UserInfo:          mb.UserInfo{CellNumber: "0766140000"},
+		UserExisted:       true,
+		Pricelist:         mb.Pricelist{Catalogue: selections, CatalogueID: catalogueID},
+		CurrentOrder:      mb.CustomerOrder{OrderID: 1, CellNumber: "0766140000"},
+		MessageBody:       message,
+		TrackAvailability: true,
+	}
+}
+
 var GardeningSelection = mb.CatalogueSelection{
 	Preamble: grdngSlctnPreamble,
 	Items: []mb.CatalogueItem{
@@ -0,0 +1,220 @@
+package menubotlib_test
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupStock(t *testing.T) *sql.DB {
+	db := setupTestDB(t, crtCustomerOrderTbl, crtCatalogueStockTbl, crtStockReservationTbl)
+	assert.NoError(t, mb.SetStockLevel(db, catalogueID, 9, 0, 12))
+	assert.NoError(t, mb.SetStockLevel(db, catalogueID, 10, 1, 3))
+	assert.NoError(t, mb.SetStockLevel(db, catalogueID, 11, 1, 0))
+	return db
+}
+
+func stockConvo(message string) *mb.ConversationContext {
+	return &mb.ConversationContext{
+		UserInfo:     mb.UserInfo{CellNumber: "0766140000"},
+		UserExisted:  true,
+		Pricelist:    mb.Pricelist{Catalogue: selections, CatalogueID: catalogueID},
+		CurrentOrder: mb.CustomerOrder{OrderID: 1, CellNumber: "0766140000"},
+		MessageBody:  message,
+		TrackStock:   true,
+	}
+}
+
+func Test_SoldOutInPriceList(t *testing.T) {
+	db := setupStock(t)
+
+	levels, err := mb.GetStockLevelsFromDB(db, catalogueID)
+	assert.NoError(t, err)
+	marked := levels.WithStock(selections)
+
+	sourStrips := marked[4].Items[1]
+	assert.True(t, sourStrips.SoldOut)
+	assert.Equal(t, "11: Sour space strips - 400mg - SOLD OUT\n   1. 10-Pack @ R180 - SOLD OUT\n\n", sourStrips.CatalogueItemAsAString())
+	assert.False(t, marked[4].Items[0].SoldOut)
+
+	// The catalogue itself is left alone
+	assert.False(t, selections[4].Items[1].SoldOut)
+
+	response := mb.GetResponseToMsg(stockConvo("item 11?"), db, mb.CheckoutInfo{}, true)
+	assert.Contains(t, response, "11: Sour space strips - 400mg - SOLD OUT")
+}
+
+func Test_UpdateOrderRejectedOverStock(t *testing.T) {
+	db := setupStock(t)
+
+	response := mb.GetResponseToMsg(stockConvo("update order 10:1x5, 9:20, 11:1x1"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Sorry, only 12 of 9: Unchargeable cellphone @ R150 each is left, only 3 of 10: Fruit toffees - 400mg, 10-Pack @ R200 is left, 11: Sour space strips - 400mg, 10-Pack @ R180 is sold out. Please change your order.", response)
+
+	// Untracked items can always be ordered
+	response = mb.GetResponseToMsg(stockConvo("update order 10:1x3, 1:50"), db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "successfully updated current order", response)
+
+	convo := stockConvo("add 10:1x1")
+	assert.NoError(t, convo.CurrentOrder.SetCurrentOrderFromDB(db, "0766140000", true))
+	response = mb.GetResponseToMsg(convo, db, mb.CheckoutInfo{}, true)
+	assert.Equal(t, "Sorry, only 3 of 10: Fruit toffees - 400mg, 10-Pack @ R200 is left. Please change your order.", response)
+}
+
+func Test_StockReservation(t *testing.T) {
+	db := setupStock(t)
+
+	order := mb.CustomerOrder{OrderID: 1, CellNumber: "0766140000", CatalogueID: catalogueID}
+	err := order.UpdateOrInsertCurrentOrder(db, order.CellNumber, mb.OrderItems{
+		MenuIndications: []mb.MenuIndication{
+			{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x2")},
+			{ItemMenuNum: 9, ItemAmount: mb.WeightQuantity(5)},
+		},
+	}, true)
+	assert.NoError(t, err)
+
+	// Confirming twice only reserves once
+	assert.NoError(t, order.ReserveStock(db, selections))
+	assert.NoError(t, order.ReserveStock(db, selections))
+	available := func(itemID, optionNum int) int {
+		levels, err := mb.GetStockLevelsFromDB(db, catalogueID)
+		assert.NoError(t, err)
+		amount, _ := levels.Available(itemID, optionNum)
+		return amount
+	}
+	assert.Equal(t, 1, available(10, 1))
+	assert.Equal(t, 7, available(9, 0))
+
+	// The order's own reservation counts as available to it
+	err = mb.CheckStock(db, catalogueID, order.OrderID, order.OrderItems.MenuIndications, selections)
+	assert.NoError(t, err)
+
+	// Another customer can't take reserved stock
+	other := mb.CustomerOrder{OrderID: 2, CatalogueID: catalogueID, OrderItems: mb.OrderItems{
+		MenuIndications: []mb.MenuIndication{{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x2")}},
+	}}
+	err = other.ReserveStock(db, selections)
+	assert.EqualError(t, err, "Sorry, only 1 of 10: Fruit toffees - 400mg, 10-Pack @ R200 is left. Please change your order.")
+
+	assert.NoError(t, order.ReleaseStock(db))
+	assert.Equal(t, 3, available(10, 1))
+
+	// Payment takes the stock off the shelf
+	assert.NoError(t, order.ReserveStock(db, selections))
+	assert.NoError(t, order.CommitStock(db))
+	assert.Equal(t, 1, available(10, 1))
+	var onHand, reserved int
+	err = db.QueryRow(`SELECT onhand, reserved FROM cataloguestock WHERE catalogueitemID = 10`).Scan(&onHand, &reserved)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 0}, []int{onHand, reserved})
+}
+
+func Test_CommitStockAfterReservationExpired(t *testing.T) {
+	db := setupStock(t)
+	available := func(itemID, optionNum int) int {
+		levels, err := mb.GetStockLevelsFromDB(db, catalogueID)
+		assert.NoError(t, err)
+		amount, _ := levels.Available(itemID, optionNum)
+		return amount
+	}
+
+	order := mb.CustomerOrder{OrderID: 1, CellNumber: "0766140000", CatalogueID: catalogueID}
+	err := order.UpdateOrInsertCurrentOrder(db, order.CellNumber, mb.OrderItems{
+		MenuIndications: []mb.MenuIndication{
+			{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x2")},
+			{ItemMenuNum: 9, ItemAmount: mb.WeightQuantity(5)},
+		},
+	}, true)
+	assert.NoError(t, err)
+	assert.NoError(t, order.ReserveStock(db, selections))
+
+	released, err := mb.ReleaseExpiredReservations(db, -time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, released)
+	assert.Equal(t, 3, available(10, 1))
+
+	// The late payment still takes its stock off the shelf
+	assert.NoError(t, order.CommitStock(db))
+	assert.Equal(t, 1, available(10, 1))
+	assert.Equal(t, 7, available(9, 0))
+
+	// What has run out since is reported, the rest is taken and untracked items are left alone
+	late := mb.CustomerOrder{OrderID: 2, CatalogueID: catalogueID, OrderItems: mb.OrderItems{
+		MenuIndications: []mb.MenuIndication{
+			{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x2")},
+			{ItemMenuNum: 9, ItemAmount: mb.WeightQuantity(5)},
+			{ItemMenuNum: 1, ItemAmount: mb.WeightQuantity(50)},
+		},
+	}}
+	err = late.CommitStock(db)
+	var shortfall *mb.StockShortfallError
+	assert.ErrorAs(t, err, &shortfall)
+	assert.Equal(t, []mb.StockShortfall{{Item: "item 10", Left: 1}}, shortfall.Shortfalls)
+	assert.Equal(t, 1, available(10, 1))
+	assert.Equal(t, 2, available(9, 0))
+}
+
+func Test_ConcurrentStockReservation(t *testing.T) {
+	db := setupSharedTestDB(t, crtCustomerOrderTbl, crtCatalogueStockTbl, crtStockReservationTbl)
+	assert.NoError(t, mb.SetStockLevel(db, catalogueID, 10, 1, 3))
+
+	var wg sync.WaitGroup
+	results := make([]error, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(orderID int) {
+			defer wg.Done()
+			order := mb.CustomerOrder{OrderID: orderID, CatalogueID: catalogueID, OrderItems: mb.OrderItems{
+				MenuIndications: []mb.MenuIndication{{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x1")}},
+			}}
+			results[orderID-1] = order.ReserveStock(db, selections)
+		}(i + 1)
+	}
+	wg.Wait()
+
+	reserved := 0
+	for _, err := range results {
+		if err == nil {
+			reserved++
+			continue
+		}
+		var shortfall *mb.StockShortfallError
+		assert.ErrorAs(t, err, &shortfall)
+	}
+	assert.Equal(t, 3, reserved)
+
+	var onHand, held int
+	err := db.QueryRow(`SELECT onhand, reserved FROM cataloguestock WHERE catalogueitemID = 10`).Scan(&onHand, &held)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 3}, []int{onHand, held})
+}
+
+func Test_ReleaseExpiredReservations(t *testing.T) {
+	db := setupStock(t)
+
+	order := mb.CustomerOrder{OrderID: 1, CellNumber: "0766140000", CatalogueID: catalogueID}
+	err := order.UpdateOrInsertCurrentOrder(db, order.CellNumber, mb.OrderItems{
+		MenuIndications: []mb.MenuIndication{{ItemMenuNum: 10, ItemAmount: mb.MustParseQuantity("1x3")}},
+	}, true)
+	assert.NoError(t, err)
+	assert.NoError(t, order.ReserveStock(db, selections))
+
+	released, err := mb.ReleaseExpiredReservations(db, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, released)
+
+	_, err = db.Exec(`UPDATE stockreservation SET reservedat = ?`, time.Now().Add(-2*time.Hour).UTC())
+	assert.NoError(t, err)
+	released, err = mb.ReleaseExpiredReservations(db, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, released)
+
+	levels, err := mb.GetStockLevelsFromDB(db, catalogueID)
+	assert.NoError(t, err)
+	amount, tracked := levels.Available(10, 1)
+	assert.True(t, tracked)
+	assert.Equal(t, 3, amount)
+}
@@ -0,0 +1,147 @@
+package menubotlib_test
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	mb "github.com/JeremyJalpha/MenuBotLib"
+	"github.com/stretchr/testify/assert"
+)
+
+const taxYAML = `
+mode: %s
+rate: 15
+sections:
+  Edibles: 0
+seller: Jalpha Trading
+sellerAddress: 1 Kloof Street, Gardens
+registrationNumber: "4123456789"
+`
+
+func taxConfig(t *testing.T, mode string) *mb.TaxConfig {
+	tax, err := mb.ParseTaxConfig([]byte(fmt.Sprintf(taxYAML, mode)), "yaml")
+	assert.NoError(t, err)
+	return tax
+}
+
+func taxConvo(message string, tax *mb.TaxConfig, promotions []mb.Promotion) *mb.ConversationContext {
+	return &mb.ConversationContext{
+		UserInfo:     mb.UserInfo{CellNumber: "0766140000"},
+		UserExisted:  true,
+		Pricelist:    mb.Pricelist{Catalogue: selections, CatalogueID: catalogueID},
+		CurrentOrder: mb.CustomerOrder{OrderID: 1, CellNumber: "0766140000"},
+		MessageBody:  message,
+		Tax:          tax,
+		Promotions:   promotions,
+	}
+}
+
+func setupTax(t *testing.T) *sql.DB {
+	return setupTestDB(t, crtCustomerOrderTbl, crtTaxInvoiceTbl)
+}
+
+func Test_ParseTaxConfig(t *testing.T) {
+	tax := taxConfig(t, "inclusive")
+	assert.Equal(t, "VAT", tax.Name)
+	assert.Equal(t, 0.0, tax.RateFor(EdiblesSelection.Items[0]))
+	assert.Equal(t, 15.0, tax.RateFor(TechSelection.Items[0]))
+
+	_, err := mb.ParseTaxConfig([]byte("mode: gross\nrate: 15"), "yaml")
+	assert.EqualError(t, err, "unknown tax mode gross, expected inclusive or exclusive")
+	_, err = mb.ParseTaxConfig([]byte(`{"rate": 15, "items": {"12": 115}}`), "json")
+	assert.EqualError(t, err, "the tax rate of item 12: 115% is not between 0 and 100")
+}
+
+func Test_TaxOnOrderSummary(t *testing.T) {
+	db := setupTax(t)
+
+	tests := []struct {
+		mode     string
+		expected string
+	}{
+		// Prices include the tax, the receipt shows how much of it is tax
+		{"inclusive", "Subtotal: R350\nTotal: R350\nIncludes VAT at 15%: R19.57\n/n/nCheckout initiation failed"},
+		// Tax is added to the total to the cent
+		{"exclusive", "Subtotal: R350\nVAT at 15%: R22.50\nTotal: R372.50\n/n/nCheckout initiation failed"},
+	}
+	for _, test := range tests {
+		tax := taxConfig(t, test.mode)
+		mb.GetResponseToMsg(taxConvo("update order 9:1, 10:1x1", tax, nil), db, mb.CheckoutInfo{}, true)
+		response := mb.GetResponseToMsg(taxConvo("checkoutnow?", tax, nil), db, mb.CheckoutInfo{}, true)
+		assert.Equal(t, test.expected, response, test.mode)
+	}
+
+	// 15% on R550 is R82.50, it isn't rounded up to R83
+	tax, err := mb.ParseTaxConfig([]byte("mode: exclusive\nrate: 15"), "yaml")
+	assert.NoError(t, err)
+	mb.GetResponseToMsg(taxConvo("update order 9:1, 10:1x2", tax, nil), db, mb.CheckoutInfo{}, true)
+	response := mb.GetResponseToMsg(taxConvo("checkoutnow?", tax, nil), db, mb.CheckoutInfo{}, true)
+	assert.Contains(t, response, "Subtotal: R550\nVAT at 15%: R82.50\nTotal: R632.50\n")
+}
+
+func Test_TaxInvoice(t *testing.T) {
+	db := setupTax(t)
+
+	tax := taxConfig(t, "inclusive")
+	promotions := []mb.Promotion{{Description: "10% off", Type: mb.PercentageDiscount, Percent: 10}}
+	mb.GetResponseToMsg(taxConvo("update order 9:1, 10:1x1", tax, promotions), db, mb.CheckoutInfo{}, true)
+	var order mb.CustomerOrder
+	assert.NoError(t, order.SetCurrentOrderFromDB(db, "0766140000", true))
+
+	ui := mb.UserInfo{CellNumber: "0766140000"}
+	prlst := mb.Pricelist{Catalogue: selections, CatalogueID: catalogueID}
+	invoice, err := order.IssueTaxInvoice(db, ui, prlst, tax, promotions...)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, invoice.Number)
+	assert.Equal(t, 31500, invoice.Total)
+
+	// The discount is shared across the rates in proportion to what each comes to
+	expected := "TAX INVOICE\n\nJalpha Trading\n1 Kloof Street, Gardens\nVAT registration number: 4123456789\n\n" +
+		"Invoice number: 1\nDate: " + invoice.IssuedAt.Format("2006-01-02") + "\nOrder: 1\nCustomer: 0766140000\n\n" +
+		"1 x Unchargeable cellphone @ R150 each: R150 (VAT 15%)\n1 x Fruit toffees - 400mg, 10-Pack @ R200: R200 (VAT 0%)\nDiscounts: -R35\n\n" +
+		"VAT at 0% on R180.00: R0.00\nVAT at 15% on R117.39: R17.61\nTotal excl. VAT: R297.39\nTotal VAT: R17.61\nTotal: R315"
+	assert.Equal(t, expected, invoice.Text())
+
+	// A repeated payment notification gets the invoice already issued, the next order the next number
+	again, err := order.IssueTaxInvoice(db, ui, prlst, tax)
+	assert.NoError(t, err)
+	assert.Equal(t, invoice.Text(), again.Text())
+	next := mb.CustomerOrder{OrderID: 2, CellNumber: "0766140001", OrderItems: order.OrderItems}
+	nextInvoice, err := next.IssueTaxInvoice(db, mb.UserInfo{CellNumber: "0766140001"}, prlst, tax)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, nextInvoice.Number)
+
+	stored, err := mb.GetTaxInvoiceFromDB(db, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, invoice.Text(), stored.Text())
+
+	pdf := invoice.PDF()
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	assert.Contains(t, string(pdf), "(VAT at 15% on R117.39: R17.61) Tj")
+}
+
+type documentSender struct {
+	textOnlySender
+	filenames []string
+}
+
+func (s *documentSender) SendDocument(to, filename string, data []byte, caption string) error {
+	s.filenames = append(s.filenames, filename)
+	return nil
+}
+
+func Test_SendTaxInvoice(t *testing.T) {
+	invoice := mb.TaxInvoice{Number: 7, OrderID: 3, TaxName: "VAT"}
+
+	documents := &documentSender{}
+	assert.NoError(t, mb.SendTaxInvoice(documents, "0766140000", invoice))
+	assert.Equal(t, []string{"tax-invoice-7.pdf"}, documents.filenames)
+	assert.Empty(t, documents.sent)
+
+	text := &textOnlySender{}
+	assert.NoError(t, mb.SendTaxInvoice(text, "0766140000", invoice))
+	assert.Equal(t, []string{invoice.Text()}, text.sent)
+}